@@ -7,6 +7,8 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/ggfevans/endorse/internal/app"
+	"github.com/ggfevans/endorse/internal/cli"
+	"github.com/ggfevans/endorse/internal/config"
 )
 
 var (
@@ -16,25 +18,58 @@ var (
 )
 
 func main() {
+	args := os.Args[1:]
+
+	// --profile may also come before a subcommand name
+	if len(args) > 1 && strings.HasPrefix(args[0], "--profile=") && cli.IsCommand(args[1]) {
+		args = append([]string{args[1], args[0]}, args[2:]...)
+	}
+
+	// Headless subcommands skip the TUI entirely
+	if len(args) > 0 && cli.IsCommand(args[0]) {
+		os.Exit(cli.Run(args, os.Stdin, os.Stdout, os.Stderr))
+	}
+
 	demoMode := false
+	noCache := false
+	highContrast := false
 	themeName := ""
-	for _, arg := range os.Args[1:] {
+	for _, arg := range args {
 		switch {
 		case arg == "-v" || arg == "--version":
 			fmt.Printf("endorse %s (%s, %s)\n", version, commit, date)
 			return
 		case arg == "--demo":
 			demoMode = true
+		case arg == "--no-cache":
+			noCache = true
+		case arg == "--high-contrast":
+			highContrast = true
 		case strings.HasPrefix(arg, "--theme="):
 			themeName = strings.TrimPrefix(arg, "--theme=")
+			if _, err := config.LoadTheme(themeName); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(2)
+			}
+		case strings.HasPrefix(arg, "--profile="):
+			if err := config.SetProfile(strings.TrimPrefix(arg, "--profile=")); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(2)
+			}
 		}
 	}
 
-	m := app.New(app.Options{DemoMode: demoMode, ThemeName: themeName})
+	// A broken config, such as two actions on one key, is reported up front
+	if _, err := config.Load(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: config.toml: %v\n", err)
+		os.Exit(2)
+	}
+
+	m := app.New(app.Options{DemoMode: demoMode, ThemeName: themeName, NoCache: noCache, HighContrast: highContrast})
 
 	p := tea.NewProgram(m,
 		tea.WithAltScreen(),
-		tea.WithMouseCellMotion(),
+		tea.WithMouseAllMotion(), // motion without a button held, for hover
 	)
 
 	// Send program reference for real-time event bridging
@@ -1,7 +1,11 @@
 package app
 
 import (
+	"cmp"
 	"context"
+	"errors"
+	"fmt"
+	"slices"
 	"sort"
 	"time"
 
@@ -11,17 +15,39 @@ import (
 	"github.com/rs/zerolog"
 	"go.mau.fi/mautrix-linkedin/pkg/linkedingo"
 
+	"github.com/ggfevans/endorse/internal/cache"
 	"github.com/ggfevans/endorse/internal/config"
+	"github.com/ggfevans/endorse/internal/cookieimport"
+	"github.com/ggfevans/endorse/internal/folders"
 	"github.com/ggfevans/endorse/internal/linkedin"
+	"github.com/ggfevans/endorse/internal/outbox"
+	"github.com/ggfevans/endorse/internal/search"
 	"github.com/ggfevans/endorse/internal/ui/compose"
 	"github.com/ggfevans/endorse/internal/ui/convlist"
 	"github.com/ggfevans/endorse/internal/ui/header"
 	"github.com/ggfevans/endorse/internal/ui/layout"
 	"github.com/ggfevans/endorse/internal/ui/modal"
+	"github.com/ggfevans/endorse/internal/ui/sidebar"
 	"github.com/ggfevans/endorse/internal/ui/statusbar"
 	"github.com/ggfevans/endorse/internal/ui/styles"
 	"github.com/ggfevans/endorse/internal/ui/thread"
-	"github.com/ggfevans/endorse/internal/util"
+)
+
+const (
+	// messagePageSize is the number of messages requested per page.
+	messagePageSize = 20
+
+	// loadMoreThreshold is how close to the end of the conversation list the
+	// selection gets before the next page of conversations is requested.
+	loadMoreThreshold = 3
+
+	// cacheFlushDelay batches cache writes so bursts of updates hit disk once.
+	cacheFlushDelay = 2 * time.Second
+
+	// Our own typing notifications are re-sent at most this often while
+	// typing continues; recipients drop the indicator a few seconds after
+	// the last one.
+	typingSendInterval = 3 * time.Second
 )
 
 // Model is the root application model.
@@ -33,8 +59,9 @@ type Model struct {
 	quitting bool
 
 	// Config
-	cfg   config.Config
-	theme config.Theme
+	cfg      config.Config
+	theme    config.Theme
+	terminal styles.Terminal
 
 	// Styles
 	styles styles.Styles
@@ -43,19 +70,30 @@ type Model struct {
 	dims layout.Dimensions
 
 	// Child components
-	header       header.Model
-	statusBar    statusbar.Model
-	convList     convlist.Model
-	thread       thread.Model
-	compose      compose.Model
-	authModal    modal.AuthModel
-	confirmModal modal.ConfirmModel
+	header        header.Model
+	statusBar     statusbar.Model
+	sidebar       sidebar.Model
+	convList      convlist.Model
+	thread        thread.Model
+	compose       compose.Model
+	authModal     modal.AuthModel
+	confirmModal  modal.ConfirmModel
+	profilesModal modal.ProfilesModel
+	helpModal     modal.HelpModel
+	paletteModal  modal.PaletteModel
 
 	// LinkedIn client
 	client   linkedin.MessagingClient
+	program  *tea.Program // handed to clients rebuilt after startup
+	title    string       // last terminal title set
 	ctx      context.Context
 	demoMode bool
 
+	// Realtime reconnect supervisor, and the conversation whose newest page
+	// is being refetched to fill the gap after a reconnect
+	reconnect      reconnectState
+	backfillConvID string
+
 	// User info
 	username string
 	userURN  linkedingo.URN
@@ -64,74 +102,149 @@ type Model struct {
 	conversations []linkedin.DisplayConversation
 	prevCursor    string // for message pagination
 
+	// Whether a page of conversations has come from the server for this
+	// profile, rather than only from the cache
+	listedFromServer bool
+
 	// Pending delete (conversation ID awaiting confirmation)
 	pendingDeleteID string
 
+	// Command palette picks, most recent first, which it lists first
+	recentPicks []string
+
 	// Pending credentials (saved between auth submit and validation)
 	pendingCreds *config.Credentials
 
+	// Re-authenticating after the session expired: the auth form overlays
+	// the inbox, which stays loaded until the same account signs back in
+	reauth bool
+
 	// Typing indicator generation counter (for debouncing expiry timers)
 	typingGeneration int
+
+	// Profile generation counter, bumped on every account switch so
+	// in-flight client results for the old account are dropped
+	profileGeneration int
+
+	// Latest seen receipt per conversation, keyed by reader URN
+	receipts map[string]map[string]thread.Receipt
+
+	// Our own typing notifications: the conversation last notified and when
+	typingConvID string
+	typingSentAt time.Time
+
+	// Locally known conversations and messages, used for search. Persisted
+	// to disk unless disabled (demo mode or --no-cache).
+	cacheEnabled    bool
+	cache           cache.Snapshot
+	cacheGeneration int
+	cacheSaves      *saveQueue
+
+	// Messages not yet confirmed as sent, persisted outside demo mode
+	outbox        outbox.Outbox
+	outboxEnabled bool
+	outboxSaves   *saveQueue
+
+	// Selected sidebar folder, and locally kept pinned/muted membership
+	folder         int
+	folderState    folders.State
+	foldersEnabled bool
+	folderSaves    *saveQueue
 }
 
 // Options configures the application.
 type Options struct {
-	DemoMode  bool
-	ThemeName string
+	DemoMode     bool
+	ThemeName    string
+	NoCache      bool
+	HighContrast bool
 }
 
 // New creates a new application model.
 func New(opts Options) Model {
-	cfg, _ := config.Load()
+	cfg, cfgErr := config.Load()
 	if opts.ThemeName != "" {
 		cfg.ThemeName = opts.ThemeName
 	}
-	theme := config.ThemeByName(cfg.ThemeName)
-	s := styles.New(theme)
+	terminal := styles.DetectTerminal()
+	terminal.HighContrast = terminal.HighContrast || cfg.Display.HighContrast || opts.HighContrast
+
+	theme := styles.DefaultTheme(terminal)
+	var themeErr error
+	if cfg.ThemeName != "" {
+		if theme, themeErr = config.LoadTheme(cfg.ThemeName); themeErr != nil {
+			theme = styles.DefaultTheme(terminal)
+		}
+	}
+	s := styles.NewFor(theme, terminal)
 
 	noop := zerolog.Nop()
 	ctx := noop.WithContext(context.Background())
 
-	startState := StateAuth
-	if opts.DemoMode {
-		startState = StateLoading
-	} else {
-		creds, _ := config.LoadCredentials()
-		if !creds.IsEmpty() {
-			startState = StateLoading
-		}
-	}
-
 	m := Model{
-		state:        startState,
-		focus:        FocusConvList,
-		cfg:          cfg,
-		theme:        theme,
-		styles:       s,
-		ctx:          ctx,
-		demoMode:     opts.DemoMode,
-		header:       header.New(s),
-		statusBar:    statusbar.New(s),
-		convList:     convlist.New(s),
-		thread:       thread.New(s),
-		compose:      compose.New(s),
-		authModal:    modal.NewAuth(s),
-		confirmModal: modal.NewConfirm(s),
+		focus:         FocusConvList,
+		cfg:           cfg,
+		theme:         theme,
+		terminal:      terminal,
+		styles:        s,
+		ctx:           ctx,
+		demoMode:      opts.DemoMode,
+		header:        header.New(s),
+		statusBar:     statusbar.New(s),
+		sidebar:       sidebar.New(s),
+		convList:      convlist.New(s),
+		thread:        thread.New(s),
+		compose:       compose.New(s),
+		authModal:     modal.NewAuth(s),
+		confirmModal:  modal.NewConfirm(s),
+		profilesModal: modal.NewProfiles(s, config.ValidateProfileName),
+		helpModal:     modal.NewHelp(s),
+		paletteModal:  modal.NewPalette(s),
+		cacheSaves:    &saveQueue{},
+		outboxSaves:   &saveQueue{},
+		folderSaves:   &saveQueue{},
 	}
 
 	m.thread.SetComposeView(m.compose.View())
+	m.thread.SetAbsoluteTimes(cfg.Display.AbsoluteTimes)
+	m.thread.SetGroupGap(time.Duration(cfg.Display.GroupGapMinutes) * time.Minute)
+	m.convList.SetAbsoluteTimes(cfg.Display.AbsoluteTimes)
 
 	if opts.DemoMode {
 		m.client = linkedin.NewDemoClient()
 	}
 
+	m.outboxEnabled = !opts.DemoMode
+	m.foldersEnabled = !opts.DemoMode
+	m.cacheEnabled = cfg.Cache.Enabled && !opts.NoCache && !opts.DemoMode
+
+	// A bad backend makes loading credentials fail, which the auth form shows
+	_ = config.UseCredentialBackend(cfg.Credentials)
+	m.loadProfile()
+
+	m.statusBar.SetHints(m.hintsFor(m.focus))
+	m.confirmModal.SetKeys(cfg.Keys.Label(config.ActionConfirm), cfg.Keys.Label(config.ActionCancel))
+	m.thread.SetUnsentKeys(cfg.Keys.Label(config.ActionRetry), cfg.Keys.Label(config.ActionDiscard))
+
+	if err := cmp.Or(cfgErr, themeErr); err != nil {
+		m.statusBar.SetError(err.Error())
+	}
 	return m
 }
 
 // Init implements tea.Model.
 func (m Model) Init() tea.Cmd {
+	cmds := []tea.Cmd{clockTick(), m.initAuth()}
+	if m.statusBar.Error() != "" {
+		cmds = append(cmds, clearErrorAfter())
+	}
+	return tea.Batch(cmds...)
+}
+
+// initAuth starts validating demo or stored credentials, if there are any.
+func (m Model) initAuth() tea.Cmd {
 	if m.demoMode {
-		return m.client.ValidateAuth()
+		return m.forProfile(m.client.ValidateAuth())
 	}
 
 	creds, _ := config.LoadCredentials()
@@ -145,26 +258,57 @@ func (m Model) Init() tea.Cmd {
 				return linkedin.AuthFailedMsg{Err: err}
 			}
 		}
-		return client.ValidateAuth()
+		return m.forProfile(client.ValidateAuth())
 	}
 	return nil
 }
 
-// Update implements tea.Model.
+// Update implements tea.Model. It keeps the terminal title in step with the
+// unread total after every message.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	result, cmd := m.update(msg)
+	next, ok := result.(Model)
+	if !ok {
+		return result, cmd
+	}
+	if title := next.windowTitle(); title != next.title {
+		next.title = title
+		return next, tea.Batch(cmd, tea.SetWindowTitle(title))
+	}
+	return next, cmd
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.dims = layout.Calculate(msg.Width, msg.Height)
 		m.ready = true
 		m.updateSizes()
+		if m.focus == FocusSidebar && m.dims.Mode != layout.ThreePanel {
+			// The sidebar is hidden at this width
+			m.setFocus(FocusConvList)
+		}
 		m.authModal.SetSize(msg.Width, msg.Height)
 		m.confirmModal.SetSize(msg.Width, msg.Height)
+		m.profilesModal.SetSize(msg.Width, msg.Height)
+		m.helpModal.SetSize(msg.Width, msg.Height)
+		m.paletteModal.SetSize(msg.Width, msg.Height)
 		return m, nil
 
 	case tea.KeyMsg:
 		return m.handleKey(msg)
 
+	case tea.MouseMsg:
+		m.handleMouse(msg)
+		return m, nil
+
+	case ClockTickMsg:
+		// The list formats times as it renders; the thread caches its lines
+		m.thread.Refresh()
+		return m, clockTick()
+
 	case ProgramRefMsg:
+		m.program = msg.Program
 		if m.client != nil {
 			m.client.SetProgram(msg.Program)
 		}
@@ -174,10 +318,31 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.statusBar.ClearError()
 		return m, nil
 
+	case ClearNoticeMsg:
+		if m.statusBar.Notice() == msg.Notice {
+			m.statusBar.SetNotice("")
+		}
+		return m, nil
+
+	case ExportDoneMsg:
+		return m, m.handleExportDone(msg)
+
+	case modal.ProfileSelectedMsg:
+		return m.switchProfile(msg)
+
+	case modal.PaletteSelectedMsg:
+		return m.runPaletteItem(msg.Item)
+
 	// Auth flow messages
 	case modal.AuthSubmitMsg:
 		return m.handleAuthSubmit(msg)
 
+	case modal.AuthImportMsg:
+		return m.handleAuthImport(msg)
+
+	case modal.PassphraseSubmitMsg:
+		return m.handlePassphraseSubmit(msg)
+
 	case linkedin.AuthValidatedMsg:
 		return m.handleAuthValidated(msg)
 
@@ -189,6 +354,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.handleConversationsLoaded(msg)
 
 	case linkedin.ConversationsLoadFailedMsg:
+		m.convList.SetHistory(false, m.convList.HasMore())
 		m.statusBar.SetError(msg.Err.Error())
 		return m, clearErrorAfter()
 
@@ -197,6 +363,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.handleMessagesLoaded(msg)
 
 	case linkedin.MessagesLoadFailedMsg:
+		m.thread.SetLoadingOlder(false)
 		m.statusBar.SetError(msg.Err.Error())
 		return m, clearErrorAfter()
 
@@ -208,6 +375,20 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.statusBar.SetError("Failed to send: " + msg.Err.Error())
 		return m, clearErrorAfter()
 
+	// Outbox messages
+	case OutboxSentMsg:
+		return m.handleOutboxSent(msg)
+
+	case OutboxSendFailedMsg:
+		return m.handleOutboxSendFailed(msg)
+
+	case OutboxRetryMsg:
+		item, ok := m.outbox.Get(msg.ItemID)
+		if !ok || item.Failed || item.Sending || item.Attempts != msg.Attempts {
+			return m, nil // sent, discarded, or superseded by a manual retry
+		}
+		return m, m.sendOutboxItem(msg.ItemID, false)
+
 	// Real-time messages
 	case linkedin.RealtimeMessageMsg:
 		return m.handleRealtimeMessage(msg)
@@ -224,12 +405,27 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		})
 		return m, tea.Batch(spinnerCmd, expiryCmd)
 
+	case linkedin.RealtimeSeenMsg:
+		return m.handleSeen(msg)
+
 	case TypingExpiredMsg:
 		if msg.Generation == m.typingGeneration {
 			m.thread.ClearTyping()
 		}
 		return m, nil
 
+	case CacheFlushMsg:
+		if msg.Generation == m.cacheGeneration {
+			return m, m.saveCache()
+		}
+		return m, nil
+
+	case ProfileMsg:
+		if msg.Generation != m.profileGeneration || msg.Msg == nil {
+			return m, nil
+		}
+		return m.update(msg.Msg)
+
 	case spinner.TickMsg:
 		var cmd tea.Cmd
 		m.thread, cmd = m.thread.Update(msg)
@@ -238,12 +434,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case linkedin.RealtimeConnectedMsg:
 		m.header.SetConnected(true)
 		m.statusBar.SetConnected(true)
-		return m, nil
+		if !m.reconnect.pending() {
+			return m, nil
+		}
+		m.resetReconnect()
+		return m, m.backfill()
 
 	case linkedin.RealtimeDisconnectedMsg:
 		m.header.SetConnected(false)
 		m.statusBar.SetConnected(false)
-		return m, nil
+		return m, m.scheduleReconnect()
+
+	case ReconnectTickMsg:
+		return m, m.handleReconnectTick(msg)
 
 	case linkedin.ConversationDeletedMsg:
 		return m.handleConversationDeleted(msg)
@@ -261,13 +464,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, clearErrorAfter()
 
 	case linkedin.SessionExpiredMsg:
-		_ = config.ClearCredentials()
-		m.state = StateAuth
-		m.client = nil
-		m.header.SetConnected(false)
-		m.statusBar.SetConnected(false)
-		m.authModal.SetError("Session expired. Please re-authenticate.")
-		return m, nil
+		return m.handleSessionExpired()
 	}
 
 	// Forward to focused component
@@ -278,6 +475,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m Model) handleAuthSubmit(msg modal.AuthSubmitMsg) (tea.Model, tea.Cmd) {
 	m.authModal.SetLoading(true)
+	if msg.Passphrase != "" {
+		config.SetPassphrase(msg.Passphrase)
+	}
 
 	client, err := linkedin.New(m.ctx, msg.Cookie, msg.PageInstance, msg.XLiTrack)
 	if err != nil {
@@ -291,22 +491,97 @@ func (m Model) handleAuthSubmit(msg modal.AuthSubmitMsg) (tea.Model, tea.Cmd) {
 		PageInstance: msg.PageInstance,
 		XLiTrack:     msg.XLiTrack,
 	}
-	return m, client.ValidateAuth()
+	return m, m.forProfile(client.ValidateAuth())
+}
+
+// handleAuthImport reads a session from a cookies.txt or HAR file and signs
+// in with it as if it had been pasted, so it is validated before saving.
+func (m Model) handleAuthImport(msg modal.AuthImportMsg) (tea.Model, tea.Cmd) {
+	path, err := expandHome(msg.Path)
+	if err != nil {
+		m.authModal.SetError(err.Error())
+		return m, nil
+	}
+	session, err := cookieimport.ReadFile(path)
+	if err != nil {
+		m.authModal.SetError("Import failed: " + err.Error())
+		return m, nil
+	}
+	return m.handleAuthSubmit(modal.AuthSubmitMsg{
+		Cookie:       session.Cookie,
+		PageInstance: session.PageInstance,
+		XLiTrack:     session.XLiTrack,
+		Passphrase:   msg.Passphrase,
+	})
+}
+
+// handlePassphraseSubmit unlocks encrypted credentials, or encrypts ones
+// left in plaintext, then carries on starting up as if they had just been
+// read from disk.
+func (m Model) handlePassphraseSubmit(msg modal.PassphraseSubmitMsg) (tea.Model, tea.Cmd) {
+	config.SetPassphrase(msg.Passphrase)
+	if config.NeedsMigration() {
+		if err := config.MigrateCredentials(); err != nil {
+			config.SetPassphrase("")
+			m.authModal.SetError("Couldn't encrypt credentials: " + err.Error())
+			return m, nil
+		}
+	}
+	if _, err := config.LoadCredentials(); err != nil {
+		config.SetPassphrase("")
+		if errors.Is(err, config.ErrWrongPassphrase) {
+			m.authModal.SetError("Wrong passphrase")
+		} else {
+			m.authModal.SetError(err.Error())
+		}
+		return m, nil
+	}
+
+	m.loadProfile()
+	return m, m.initAuth()
+}
+
+// passphraseMode picks which passphrase fields the auth form needs, given
+// the result of loading credentials.
+func passphraseMode(loadErr error) modal.PassphraseMode {
+	switch {
+	case config.CredentialBackend() != config.BackendEncrypted:
+		return modal.PassphraseOff
+	case errors.Is(loadErr, config.ErrLocked), errors.Is(loadErr, config.ErrWrongPassphrase):
+		return modal.PassphraseUnlock
+	case config.HasPassphrase():
+		return modal.PassphraseOff
+	case config.NeedsMigration():
+		return modal.PassphraseMigrate
+	default:
+		return modal.PassphraseChoose
+	}
 }
 
 func (m Model) handleAuthValidated(msg linkedin.AuthValidatedMsg) (tea.Model, tea.Cmd) {
+	// The inbox kept through re-auth belongs to the expired account
+	if m.reauth && !m.userURN.IsEmpty() && msg.UserURN.String() != m.userURN.String() {
+		m.client = nil
+		m.pendingCreds = nil
+		m.authModal.SetError(fmt.Sprintf("That session is for %s, not %s. Press Esc to switch accounts instead.", msg.Username, m.username))
+		return m, nil
+	}
+	resumed := m.reauth
+	m.reauth = false
+
 	m.username = msg.Username
 	m.userURN = msg.UserURN
 	m.header.SetUsername(m.username)
 	m.statusBar.SetUsername(m.username)
 
+	var saveErr error
 	if !m.demoMode {
 		// Determine credentials source: pending (from auth modal) or stored (from disk)
 		var creds config.Credentials
 		if m.pendingCreds != nil {
 			creds = *m.pendingCreds
 			// Save newly validated credentials
-			_ = config.SaveCredentials(creds)
+			saveErr = config.SaveCredentials(creds)
 			m.pendingCreds = nil
 		} else {
 			creds, _ = config.LoadCredentials()
@@ -315,46 +590,134 @@ func (m Model) handleAuthValidated(msg linkedin.AuthValidatedMsg) (tea.Model, te
 		// Recreate client with proper URN
 		newClient, err := linkedin.NewWithURN(m.ctx, creds.Cookie, creds.PageInstance, creds.XLiTrack, msg.UserURN)
 		if err == nil {
+			newClient.SetProgram(m.program)
 			m.client = newClient
 		}
 	}
 
+	// Keep the user's place if they were already browsing the cached inbox
+	if m.state != StateMessaging {
+		m.convList.Focus()
+		m.setFocus(FocusConvList)
+	}
 	m.state = StateMessaging
 	m.header.SetConnected(true)
 	m.statusBar.SetConnected(true)
-	m.convList.Focus()
-	m.setFocus(FocusConvList)
 
 	var cmds []tea.Cmd
 	if m.client != nil {
-		cmds = append(cmds, m.client.FetchConversations())
+		if resumed {
+			// Catch up on what arrived while signed out, open thread included
+			cmds = append(cmds, m.backfill())
+		} else {
+			cmds = append(cmds, m.forProfile(m.client.FetchConversations()))
+		}
 		cmds = append(cmds, m.client.ConnectRealtime())
+
+		// Resend anything left in the outbox from a previous session
+		for _, item := range m.outbox.Items {
+			if !item.Sending && !item.Failed {
+				cmds = append(cmds, m.sendOutboxItem(item.ID, false))
+			}
+		}
+	}
+
+	if saveErr != nil {
+		m.statusBar.SetError("Signed in, but credentials weren't saved: " + saveErr.Error())
+		cmds = append(cmds, clearErrorAfter())
 	}
 
 	return m, tea.Batch(cmds...)
 }
 
 func (m Model) handleAuthFailed(msg linkedin.AuthFailedMsg) (tea.Model, tea.Cmd) {
-	m.state = StateAuth
+	if m.reauth {
+		// Stay over the inbox, dropping the client for the rejected session
+		m.client = nil
+		m.pendingCreds = nil
+	} else {
+		m.state = StateAuth
+	}
 	m.authModal.SetError("Authentication failed: " + msg.Err.Error())
 	return m, nil
 }
 
+// handleSessionExpired signs out and asks for a new session. While
+// messaging, the auth form is shown over the inbox, keeping the open
+// thread, drafts and outbox; once the account signs back in, the client is
+// rebuilt, realtime resumes and unsent messages are sent.
+func (m Model) handleSessionExpired() (tea.Model, tea.Cmd) {
+	if m.reauth {
+		return m, nil // already asked; later requests failed the same way
+	}
+	m.resetReconnect()
+	_ = config.ClearCredentials()
+	if m.client != nil {
+		m.client.DisconnectRealtime()
+		m.client = nil
+	}
+	m.stopTyping()
+	m.header.SetConnected(false)
+	m.statusBar.SetConnected(false)
+
+	m.authModal = modal.NewAuth(m.styles)
+	m.authModal.SetSize(m.dims.Width, m.dims.Height)
+	m.authModal.SetPassphraseMode(passphraseMode(nil))
+	if m.state == StateMessaging {
+		m.reauth = true
+		m.confirmModal.Hide()
+		m.pendingDeleteID = ""
+		m.authModal.SetError("Session expired. Sign in again to pick up where you left off.")
+	} else {
+		m.state = StateAuth
+		m.authModal.SetError("Session expired. Please re-authenticate.")
+	}
+	return m, nil
+}
+
 // --- Filtering ---
 
 func (m *Model) applyConversationFilter() {
+	if m.convList.Searching() {
+		m.applySearch()
+		return
+	}
+
 	var items []convlist.Conversation
 	for _, dc := range m.conversations {
-		// Filter: 0=all, 1=unread only
-		if m.convList.FilterTab() == 1 && !dc.Unread {
+		if !m.inFolder(dc, m.folder) {
 			continue
 		}
 		items = append(items, convlist.Conversation{
 			ID:          dc.ID,
 			Name:        dc.Title,
 			LastMessage: dc.LastMessage,
-			Timestamp:   util.RelativeTime(dc.LastActivityAt),
+			Timestamp:   dc.LastActivityAt,
 			Unread:      dc.Unread,
+			UnreadCount: dc.UnreadCount,
+		})
+	}
+	m.convList.SetConversations(items)
+}
+
+// applySearch fills the conversation list with hits for the current query.
+func (m *Model) applySearch() {
+	results := search.Search(m.convList.Query(), m.conversations, m.cache.Messages, time.Now())
+	unread := make(map[string]linkedin.DisplayConversation, len(m.conversations))
+	for _, dc := range m.conversations {
+		unread[dc.ID] = dc
+	}
+
+	var items []convlist.Conversation
+	for _, r := range results {
+		items = append(items, convlist.Conversation{
+			ID:          r.ConversationID,
+			Name:        r.Title,
+			LastMessage: r.Snippet,
+			Timestamp:   r.Time,
+			Unread:      unread[r.ConversationID].Unread,
+			UnreadCount: unread[r.ConversationID].UnreadCount,
+			MessageID:   r.MessageID,
 		})
 	}
 	m.convList.SetConversations(items)
@@ -363,18 +726,119 @@ func (m *Model) applyConversationFilter() {
 // --- Conversation handlers ---
 
 func (m Model) handleConversationsLoaded(msg linkedin.ConversationsLoadedMsg) (tea.Model, tea.Cmd) {
-	m.conversations = msg.Conversations
+	// The list may already hold conversations from the cache, so the first
+	// page is told apart by whether one has come from the server yet
+	firstPage := !msg.Older && !m.listedFromServer
+	if !msg.Older {
+		m.listedFromServer = true
+	}
+	if msg.Full {
+		m.conversations = dropUnlisted(m.conversations, msg.Conversations)
+	}
+	var added int
+	m.conversations, added = mergeConversations(m.conversations, msg.Conversations)
+
+	// More history may exist after a non-empty first page; an older page
+	// that adds nothing new means the end of history was reached.
+	switch {
+	case firstPage:
+		m.convList.SetHistory(false, len(msg.Conversations) > 0)
+	case msg.Older:
+		m.convList.SetHistory(false, added > 0)
+	}
+
+	m.sortConversations()
+	m.refreshConversationList()
+
+	if !m.cacheEnabled {
+		return m, nil
+	}
+	m.cache.SetConversations(m.conversations)
+	return m, m.scheduleCacheFlush()
+}
 
-	// Sort by last activity (most recent first)
-	sort.Slice(m.conversations, func(i, j int) bool {
+// sortConversations orders conversations by last activity, most recent first.
+func (m *Model) sortConversations() {
+	sort.SliceStable(m.conversations, func(i, j int) bool {
 		return m.conversations[i].LastActivityAt.After(m.conversations[j].LastActivityAt)
 	})
+}
 
-	// Apply current filter and update convlist
+// refreshConversationList re-applies the filter after conversation data
+// changed, keeping the selection on the same conversation rather than the
+// same row.
+func (m *Model) refreshConversationList() {
+	selected, hadSelection := m.convList.SelectedConversation()
 	m.applyConversationFilter()
 	m.updateFilterCounts()
+	if hadSelection {
+		m.convList.SelectID(selected.ID)
+	}
+}
 
-	return m, nil
+// conversationIndex returns the index of a conversation in m.conversations,
+// or -1 if it isn't known.
+func (m Model) conversationIndex(id string) int {
+	for i, dc := range m.conversations {
+		if dc.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// mergeConversations upserts incoming conversations into existing ones,
+// deduplicating by URN. It returns the merged slice and how many were new.
+func mergeConversations(existing, incoming []linkedin.DisplayConversation) ([]linkedin.DisplayConversation, int) {
+	index := make(map[string]int, len(existing))
+	for i, dc := range existing {
+		index[dc.URN.String()] = i
+	}
+
+	added := 0
+	for _, dc := range incoming {
+		if i, ok := index[dc.URN.String()]; ok {
+			existing[i] = dc
+			continue
+		}
+		index[dc.URN.String()] = len(existing)
+		existing = append(existing, dc)
+		added++
+	}
+	return existing, added
+}
+
+// dropUnlisted removes cached conversations that a full newest page should
+// have listed but didn't. Anything active since the page's oldest entry
+// would be on it, so a missing one was deleted or left, perhaps on another
+// device. Archived conversations aren't always on the newest page, and an
+// empty page gives nothing to compare against, so both are left alone.
+func dropUnlisted(existing, page []linkedin.DisplayConversation) []linkedin.DisplayConversation {
+	if len(page) == 0 {
+		return existing
+	}
+	listed := make(map[string]bool, len(page))
+	oldest := page[0].LastActivityAt
+	for _, dc := range page {
+		listed[dc.URN.String()] = true
+		if dc.LastActivityAt.Before(oldest) {
+			oldest = dc.LastActivityAt
+		}
+	}
+	return slices.DeleteFunc(existing, func(dc linkedin.DisplayConversation) bool {
+		return !dc.Archived && !listed[dc.URN.String()] && dc.LastActivityAt.After(oldest)
+	})
+}
+
+// loadOlderConversations requests the page of conversations before the
+// oldest one loaded, unless a request is in flight or history is exhausted.
+func (m *Model) loadOlderConversations() tea.Cmd {
+	if m.client == nil || len(m.conversations) == 0 || m.convList.LoadingMore() || !m.convList.HasMore() {
+		return nil
+	}
+	oldest := m.conversations[len(m.conversations)-1].LastActivityAt
+	m.convList.SetHistory(true, true)
+	return m.forProfile(m.client.FetchConversationsBefore(oldest))
 }
 
 // --- Message handlers ---
@@ -384,101 +848,313 @@ func (m Model) handleMessagesLoaded(msg linkedin.MessagesLoadedMsg) (tea.Model,
 		return m, nil // stale response
 	}
 
+	if !msg.Older && msg.ConversationID == m.backfillConvID {
+		return m.handleBackfillLoaded(msg)
+	}
+
 	m.prevCursor = msg.PrevCursor
+	cacheCmd := m.cacheMessages(msg.ConversationID, msg.Messages...)
+
+	if msg.Older {
+		var msgs []thread.Message
+		for _, dm := range msg.Messages {
+			msgs = append(msgs, toThreadMessage(dm))
+		}
+		m.thread.PrependMessages(msgs)
+	} else {
+		// Merge the newest page into what's cached rather than replacing the
+		// thread, which would drop older messages already on screen, such
+		// as a search hit
+		var msgs []thread.Message
+		for _, dm := range m.cache.ConversationMessages(msg.ConversationID) {
+			msgs = append(msgs, toThreadMessage(dm))
+		}
+		m.thread.MergeMessages(msgs)
+	}
+
+	// An empty cursor or an empty older page means there is nothing left to fetch
+	m.thread.SetReachedStart(m.prevCursor == "" || (msg.Older && len(msg.Messages) == 0))
+
+	// Keep paging back until a search hit older than what's loaded arrives
+	if m.thread.JumpPending() {
+		return m, tea.Batch(cacheCmd, m.loadOlderMessages())
+	}
+	return m, cacheCmd
+}
+
+// backfill refetches what may have been missed while realtime was down: the
+// conversation list and the newest page of the open thread.
+func (m *Model) backfill() tea.Cmd {
+	if m.client == nil {
+		return nil
+	}
+	cmds := []tea.Cmd{m.forProfile(m.client.FetchConversations())}
+	convID := m.thread.ConversationID()
+	if urn := m.findConversationURN(convID); !urn.IsEmpty() {
+		m.backfillConvID = convID
+		cmds = append(cmds, m.forProfile(m.client.FetchMessages(urn, time.Now(), messagePageSize)))
+	}
+	return tea.Batch(cmds...)
+}
+
+// handleBackfillLoaded merges a refetched newest page into the open thread
+// without disturbing paging state. Messages are deduplicated by URN, so any
+// that also arrived over realtime appear once.
+func (m Model) handleBackfillLoaded(msg linkedin.MessagesLoadedMsg) (tea.Model, tea.Cmd) {
+	m.backfillConvID = ""
+	cacheCmd := m.cacheMessages(msg.ConversationID, msg.Messages...)
 
 	var msgs []thread.Message
-	for _, dm := range msg.Messages {
-		msgs = append(msgs, thread.Message{
-			ID:        dm.ID,
-			Sender:    dm.Sender,
-			Body:      dm.Body,
-			Timestamp: util.RelativeTime(dm.Timestamp),
-			IsOwn:     dm.IsOwn,
-		})
+	for _, dm := range m.cache.ConversationMessages(msg.ConversationID) {
+		msgs = append(msgs, toThreadMessage(dm))
 	}
-	m.thread.SetMessages(msgs)
+	m.thread.MergeMessages(msgs)
+	return m, cacheCmd
+}
 
-	return m, nil
+// loadOlderMessages requests the page before the oldest loaded message,
+// unless a request is already in flight or the start has been reached.
+func (m *Model) loadOlderMessages() tea.Cmd {
+	if m.client == nil || m.prevCursor == "" || m.thread.LoadingOlder() || m.thread.ReachedStart() {
+		return nil
+	}
+	urn := m.findConversationURN(m.thread.ConversationID())
+	if urn.IsEmpty() {
+		return nil
+	}
+	m.thread.SetLoadingOlder(true)
+	return m.forProfile(m.client.FetchMessagesWithCursor(urn, m.prevCursor, messagePageSize))
 }
 
 func (m Model) handleMessageSent(msg linkedin.MessageSentMsg) (tea.Model, tea.Cmd) {
 	if msg.ConversationID == m.thread.ConversationID() {
-		m.thread.AppendMessage(thread.Message{
-			ID:        msg.Message.ID,
-			Sender:    msg.Message.Sender,
-			Body:      msg.Message.Body,
-			Timestamp: util.RelativeTime(msg.Message.Timestamp),
-			IsOwn:     msg.Message.IsOwn,
-		})
+		m.thread.AppendMessage(toThreadMessage(msg.Message))
 	}
-	return m, nil
+	return m, m.cacheMessages(msg.ConversationID, msg.Message)
 }
 
 func (m Model) handleRealtimeMessage(msg linkedin.RealtimeMessageMsg) (tea.Model, tea.Cmd) {
+	// The echo of a message we're sending can beat its send result, so
+	// confirm the outbox entry now rather than showing it twice
+	var sent outbox.Item
+	echo := false
+	if msg.Message.IsOwn {
+		sent, echo = m.outbox.InFlight(msg.ConversationID, msg.Message.Body)
+	}
+
 	// If the message is for the currently viewed conversation, clear typing and append
-	if msg.ConversationID == m.thread.ConversationID() {
+	viewing := msg.ConversationID == m.thread.ConversationID()
+	if viewing {
 		m.thread.ClearTyping()
-		m.thread.AppendMessage(thread.Message{
-			ID:        msg.Message.ID,
-			Sender:    msg.Message.Sender,
-			Body:      msg.Message.Body,
-			Timestamp: util.RelativeTime(msg.Message.Timestamp),
-			IsOwn:     msg.Message.IsOwn,
-		})
+		if echo {
+			m.thread.ConfirmSent(sent.ID, toThreadMessage(msg.Message))
+		} else {
+			m.thread.AppendMessage(toThreadMessage(msg.Message))
+		}
 	}
 
-	// Refresh conversations to update order and unread counts
-	if m.client != nil {
-		return m, m.client.FetchConversations()
+	cacheCmd := m.cacheMessages(msg.ConversationID, msg.Message)
+	if echo {
+		m.outbox.Remove(sent.ID)
+		m.refreshUnsent()
+		cacheCmd = tea.Batch(cacheCmd, m.saveOutbox())
+	}
+
+	// A conversation we haven't loaded yet needs a full refetch
+	i := m.conversationIndex(msg.ConversationID)
+	if i < 0 {
+		if m.client != nil {
+			return m, tea.Batch(m.forProfile(m.client.FetchConversations()), cacheCmd)
+		}
+		return m, cacheCmd
+	}
+
+	// Otherwise update it in place and move it to its new position
+	dc := &m.conversations[i]
+	dc.LastMessage = msg.Message.Body
+	if msg.Message.Timestamp.After(dc.LastActivityAt) {
+		dc.LastActivityAt = msg.Message.Timestamp
+	}
+	var markReadCmd tea.Cmd
+	if !msg.Message.IsOwn {
+		if viewing {
+			// Already on screen, so keep the server's read state in step
+			if m.client != nil && !dc.URN.IsEmpty() {
+				markReadCmd = m.forProfile(m.client.MarkRead(dc.URN))
+			}
+		} else {
+			dc.Unread = true
+			dc.UnreadCount++
+		}
+	}
+
+	m.sortConversations()
+	m.refreshConversationList()
+	if m.cacheEnabled {
+		m.cache.SetConversations(m.conversations)
+		cacheCmd = m.scheduleCacheFlush()
+	}
+
+	return m, tea.Batch(cacheCmd, markReadCmd)
+}
+
+// handleSeen records a seen receipt and refreshes the open thread's markers.
+// A receipt from the user's own account means they read it elsewhere.
+func (m Model) handleSeen(msg linkedin.RealtimeSeenMsg) (tea.Model, tea.Cmd) {
+	if msg.IsOwn {
+		if i := m.conversationIndex(msg.ConversationID); i >= 0 && m.conversations[i].Unread {
+			m.conversations[i].Unread = false
+			m.conversations[i].UnreadCount = 0
+			m.refreshConversationList()
+		}
+		return m, nil
+	}
+
+	name := msg.ReaderName
+	if name == "" {
+		name = m.participantName(msg.ConversationID, msg.ReaderURN)
+	}
+	key := msg.ReaderURN.ID()
+	if key == "" {
+		key = name
+	}
+
+	if m.receipts == nil {
+		m.receipts = make(map[string]map[string]thread.Receipt)
+	}
+	readers := m.receipts[msg.ConversationID]
+	if readers == nil {
+		readers = make(map[string]thread.Receipt)
+		m.receipts[msg.ConversationID] = readers
+	}
+	if prev, ok := readers[key]; ok && prev.SeenAt.After(msg.SeenAt) {
+		return m, nil // out of order
+	}
+	readers[key] = thread.Receipt{Reader: name, MessageID: msg.MessageID, SeenAt: msg.SeenAt}
+
+	if msg.ConversationID == m.thread.ConversationID() {
+		m.thread.SetReceipts(m.receiptsFor(msg.ConversationID))
 	}
 	return m, nil
 }
 
+// receiptsFor returns the seen receipts for a conversation, ordered by reader.
+func (m Model) receiptsFor(convID string) []thread.Receipt {
+	var out []thread.Receipt
+	for _, r := range m.receipts[convID] {
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Reader < out[j].Reader })
+	return out
+}
+
+// participantName looks up a participant's name in a known conversation.
+func (m Model) participantName(convID string, urn linkedingo.URN) string {
+	if i := m.conversationIndex(convID); i >= 0 {
+		for _, p := range m.conversations[i].Participants {
+			if p.URN.ID() == urn.ID() && p.Name != "" {
+				return p.Name
+			}
+		}
+	}
+	return "Someone"
+}
+
 // --- Key handling ---
 
 func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// Ctrl+C always quits, regardless of state
 	if msg.String() == "ctrl+c" {
-		m.quitting = true
-		if m.client != nil {
-			m.client.DisconnectRealtime()
-		}
-		return m, tea.Quit
+		return m.quit()
 	}
 
-	// Auth state: forward keys to auth modal
-	if m.state == StateAuth {
+	// The account switcher intercepts all keys when active
+	if m.profilesModal.Active() {
+		var cmd tea.Cmd
+		m.profilesModal, cmd = m.profilesModal.Update(msg)
+		return m, cmd
+	}
+
+	// Auth state or re-auth overlay: forward keys to auth modal, except Esc,
+	// which offers another account instead
+	if m.state == StateAuth || m.reauth {
+		if msg.Type == tea.KeyEsc && !m.demoMode {
+			return m, m.openProfileSwitcher()
+		}
 		var cmd tea.Cmd
 		m.authModal, cmd = m.authModal.Update(msg)
 		return m, cmd
 	}
 
+	// The help screen intercepts all keys when active; the help key closes
+	// it wherever it's bound
+	if m.helpModal.Active() {
+		if m.keyIs(msg, config.ActionHelp) {
+			m.helpModal.Hide()
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.helpModal, cmd = m.helpModal.Update(msg)
+		return m, cmd
+	}
+
+	// The command palette takes typing, and rematches on every change
+	if m.paletteModal.Active() {
+		query := m.paletteModal.Query()
+		var cmd tea.Cmd
+		m.paletteModal, cmd = m.paletteModal.Update(msg)
+		if q := m.paletteModal.Query(); q != query {
+			m.paletteModal.SetItems(m.paletteItems(q))
+		}
+		return m, cmd
+	}
+
 	// Confirm modal intercepts all keys when active
 	if m.confirmModal.Active() {
 		return m.handleConfirmKey(msg)
 	}
 
+	// Search input captures typing, including keys that are otherwise global
+	if m.focus == FocusConvList && m.convList.Searching() {
+		return m.handleSearchKey(msg)
+	}
+
 	// Global keys (messaging state)
-	if isQuitKey(msg) && m.focus != FocusCompose {
-		m.quitting = true
-		if m.client != nil {
-			m.client.DisconnectRealtime()
-		}
-		return m, tea.Quit
+	if m.keyIs(msg, config.ActionQuit) && m.focus != FocusCompose {
+		return m.quit()
+	}
+
+	if m.keyIs(msg, config.ActionSwitchAccount) && m.focus != FocusCompose {
+		return m, m.openProfileSwitcher()
+	}
+
+	if m.keyIs(msg, config.ActionNextTheme) && m.focus != FocusCompose {
+		return m, m.cycleTheme()
 	}
 
-	if isTabKey(msg) {
+	if m.keyIs(msg, config.ActionHelp) && m.focus != FocusCompose {
+		m.helpModal.Show(m.helpSections(), m.helpContext())
+		return m, nil
+	}
+
+	if m.keyIs(msg, config.ActionPalette) && m.focus != FocusCompose {
+		return m, m.openPalette()
+	}
+
+	if m.keyIs(msg, config.ActionNextPanel) {
 		m.cycleFocusForward()
 		return m, nil
 	}
 
-	if isShiftTabKey(msg) {
+	if m.keyIs(msg, config.ActionPrevPanel) {
 		m.cycleFocusBackward()
 		return m, nil
 	}
 
 	// Route to focused panel
 	switch m.focus {
+	case FocusSidebar:
+		return m.handleSidebarKey(msg)
 	case FocusConvList:
 		return m.handleConvListKey(msg)
 	case FocusThread:
@@ -492,46 +1168,99 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 func (m Model) handleConvListKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
-	case isFilterKey(msg):
-		m.convList.ToggleFilter()
+	case m.keyIs(msg, config.ActionFilter):
+		m.toggleFilterFolder()
+		return m, nil
+	case m.keyIs(msg, config.ActionTimeFormat):
+		m.toggleTimeFormat()
+		return m, nil
+	case m.keyIs(msg, config.ActionExport):
+		return m, m.exportConversation()
+	case m.keyIs(msg, config.ActionPin):
+		return m.toggleSelectedPinned()
+	case m.keyIs(msg, config.ActionMute):
+		return m.toggleSelectedMuted()
+	case m.keyIs(msg, config.ActionSearch):
+		m.convList.StartSearch()
 		m.applyConversationFilter()
 		return m, nil
-	case isDownKey(msg):
+	case m.keyIs(msg, config.ActionDown):
 		m.convList.MoveDown()
-	case isUpKey(msg):
+		if m.convList.NearBottom(loadMoreThreshold) {
+			return m, m.loadOlderConversations()
+		}
+	case m.keyIs(msg, config.ActionUp):
 		m.convList.MoveUp()
-	case isTopKey(msg):
+	case m.keyIs(msg, config.ActionTop):
 		m.convList.MoveToTop()
-	case isBottomKey(msg):
+	case m.keyIs(msg, config.ActionBottom):
 		m.convList.MoveToBottom()
-	case isEnterKey(msg):
+		return m, m.loadOlderConversations()
+	case m.keyIs(msg, config.ActionOpen):
 		return m.openSelectedConversation()
-	case isReplyKey(msg):
+	case m.keyIs(msg, config.ActionReply):
 		return m.openSelectedConversationAndReply()
-	case isMarkReadKey(msg):
+	case m.keyIs(msg, config.ActionMarkRead):
 		return m.toggleSelectedReadState()
-	case isDeleteKey(msg):
+	case m.keyIs(msg, config.ActionDelete):
 		return m.promptDeleteSelected()
 	}
 	return m, nil
 }
 
+func (m Model) handleSearchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.convList.StopSearch()
+		m.applyConversationFilter()
+	case tea.KeyEnter:
+		return m.openSelectedConversation()
+	case tea.KeyUp, tea.KeyCtrlP:
+		m.convList.MoveUp()
+	case tea.KeyDown, tea.KeyCtrlN:
+		m.convList.MoveDown()
+	case tea.KeyBackspace:
+		if q := []rune(m.convList.Query()); len(q) > 0 {
+			m.convList.SetQuery(string(q[:len(q)-1]))
+			m.applyConversationFilter()
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.convList.SetQuery(m.convList.Query() + string(msg.Runes))
+		m.applyConversationFilter()
+	}
+	return m, nil
+}
+
 func (m Model) handleThreadKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
-	case isUpKey(msg):
+	case m.keyIs(msg, config.ActionUp):
 		m.thread.ScrollUp(1)
-	case isDownKey(msg):
+		if m.thread.AtTop() {
+			return m, m.loadOlderMessages()
+		}
+	case m.keyIs(msg, config.ActionDown):
 		m.thread.ScrollDown(1)
-	case isPageUp(msg):
+	case m.keyIs(msg, config.ActionPageUp):
 		m.thread.ScrollUp(m.thread.VisibleHeight() / 2)
-	case isPageDown(msg):
+		if m.thread.AtTop() {
+			return m, m.loadOlderMessages()
+		}
+	case m.keyIs(msg, config.ActionPageDown):
 		m.thread.ScrollDown(m.thread.VisibleHeight() / 2)
-	case isReplyKey(msg):
+	case m.keyIs(msg, config.ActionReply):
 		if m.thread.HasConversation() {
 			cmd := m.activateCompose()
 			return m, cmd
 		}
-	case isEscapeKey(msg):
+	case m.keyIs(msg, config.ActionRetry):
+		return m, m.retryUnsent()
+	case m.keyIs(msg, config.ActionDiscard):
+		return m, m.discardUnsent()
+	case m.keyIs(msg, config.ActionTimeFormat):
+		m.toggleTimeFormat()
+	case m.keyIs(msg, config.ActionExport):
+		return m, m.exportConversation()
+	case m.keyIs(msg, config.ActionBack):
 		cmd := m.markCurrentConversationRead()
 		m.setFocus(FocusConvList)
 		return m, cmd
@@ -539,24 +1268,63 @@ func (m Model) handleThreadKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleMouse shows the exact time of the message under the pointer.
+func (m *Model) handleMouse(msg tea.MouseMsg) {
+	if m.state != StateMessaging || msg.Action != tea.MouseActionMotion {
+		return
+	}
+	if m.reauth || m.helpModal.Active() || m.paletteModal.Active() || m.confirmModal.Active() || m.profilesModal.Active() {
+		return // the thread is covered
+	}
+	x, y := msg.X-m.dims.ThreadLeft(), msg.Y-m.dims.ContentTop()
+	if x < 0 || x >= m.dims.ThreadWidth || y < 0 || y >= m.dims.ContentHeight {
+		return
+	}
+	m.thread.HoverAt(y - m.thread.ViewportTop())
+}
+
+// toggleTimeFormat switches every timestamp between relative and absolute.
+func (m *Model) toggleTimeFormat() {
+	m.cfg.Display.AbsoluteTimes = !m.cfg.Display.AbsoluteTimes
+	m.thread.SetAbsoluteTimes(m.cfg.Display.AbsoluteTimes)
+	m.convList.SetAbsoluteTimes(m.cfg.Display.AbsoluteTimes)
+}
+
 func (m Model) handleComposeKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
-	case isEscapeKey(msg):
+	case m.keyIs(msg, config.ActionBack):
 		m.compose.Blur()
 		m.setFocus(FocusThread)
 		return m, nil
-	case isEnterKey(msg), isSendKey(msg):
+	case m.keyIs(msg, config.ActionSend):
 		return m.sendMessage()
 	}
 
-	// Forward to textarea
+	// Forward to textarea, letting the other side know we're typing
+	before := m.compose.Value()
 	var cmd tea.Cmd
 	m.compose, cmd = m.compose.Update(msg)
+	if after := m.compose.Value(); after != before && after != "" {
+		return m, tea.Batch(cmd, m.notifyTyping())
+	}
 	return m, cmd
 }
 
 // --- Actions ---
 
+// quit disconnects realtime, flushes the cache and exits.
+func (m Model) quit() (tea.Model, tea.Cmd) {
+	m.quitting = true
+	if m.client != nil {
+		m.client.DisconnectRealtime()
+	}
+	if m.cacheEnabled && m.state == StateMessaging {
+		snap := m.cache.Prune(cache.LimitsFromConfig(m.cfg.Cache), time.Now())
+		m.cacheSaves.saveNow(func() error { return cache.Save(snap) })
+	}
+	return m, tea.Quit
+}
+
 func (m Model) openSelectedConversation() (tea.Model, tea.Cmd) {
 	// Mark previous conversation read before switching
 	markReadCmd := m.markCurrentConversationRead()
@@ -567,6 +1335,22 @@ func (m Model) openSelectedConversation() (tea.Model, tea.Cmd) {
 	}
 
 	m.thread.SetConversation(conv.ID, conv.Name)
+	m.thread.SetReceipts(m.receiptsFor(conv.ID))
+	m.prevCursor = ""
+	m.backfillConvID = ""
+	m.refreshUnsent()
+	if m.convList.Searching() {
+		m.thread.SetHighlight(search.Terms(m.convList.Query()))
+		m.thread.JumpTo(conv.MessageID)
+		m.convList.StopSearch()
+	}
+	if cached := m.cache.ConversationMessages(conv.ID); len(cached) > 0 {
+		var msgs []thread.Message
+		for _, dm := range cached {
+			msgs = append(msgs, toThreadMessage(dm))
+		}
+		m.thread.SetMessages(msgs)
+	}
 	m.compose.SetRecipient(conv.Name)
 	composeCmd := m.activateCompose()
 
@@ -574,11 +1358,13 @@ func (m Model) openSelectedConversation() (tea.Model, tea.Cmd) {
 	for i := range m.conversations {
 		if m.conversations[i].ID == conv.ID {
 			m.conversations[i].Unread = false
+			m.conversations[i].UnreadCount = 0
 			break
 		}
 	}
 	m.applyConversationFilter()
 	m.updateFilterCounts()
+	m.convList.SelectID(conv.ID)
 
 	var cmds []tea.Cmd
 	if markReadCmd != nil {
@@ -590,8 +1376,8 @@ func (m Model) openSelectedConversation() (tea.Model, tea.Cmd) {
 	if m.client != nil {
 		urn := m.findConversationURN(conv.ID)
 		if !urn.IsEmpty() {
-			cmds = append(cmds, m.client.FetchMessages(urn, time.Now(), 20))
-			cmds = append(cmds, m.client.MarkRead(urn))
+			cmds = append(cmds, m.forProfile(m.client.FetchMessages(urn, time.Now(), messagePageSize)))
+			cmds = append(cmds, m.forProfile(m.client.MarkRead(urn)))
 		}
 	}
 
@@ -606,12 +1392,13 @@ func (m *Model) markCurrentConversationRead() tea.Cmd {
 	for i := range m.conversations {
 		if m.conversations[i].ID == convID && m.conversations[i].Unread {
 			m.conversations[i].Unread = false
+			m.conversations[i].UnreadCount = 0
 			m.applyConversationFilter()
 			m.updateFilterCounts()
 			if m.client != nil {
 				urn := m.findConversationURN(convID)
 				if !urn.IsEmpty() {
-					return m.client.MarkRead(urn)
+					return m.forProfile(m.client.MarkRead(urn))
 				}
 			}
 			break
@@ -631,6 +1418,7 @@ func (m Model) toggleSelectedReadState() (tea.Model, tea.Cmd) {
 	for i := range m.conversations {
 		if m.conversations[i].ID == conv.ID {
 			m.conversations[i].Unread = !m.conversations[i].Unread
+			m.conversations[i].UnreadCount = 0
 			nowUnread = m.conversations[i].Unread
 			break
 		}
@@ -643,9 +1431,9 @@ func (m Model) toggleSelectedReadState() (tea.Model, tea.Cmd) {
 		urn := m.findConversationURN(conv.ID)
 		if !urn.IsEmpty() {
 			if nowUnread {
-				return m, m.client.MarkUnread(urn)
+				return m, m.forProfile(m.client.MarkUnread(urn))
 			}
-			return m, m.client.MarkRead(urn)
+			return m, m.forProfile(m.client.MarkRead(urn))
 		}
 	}
 
@@ -665,10 +1453,10 @@ func (m Model) promptDeleteSelected() (tea.Model, tea.Cmd) {
 
 func (m Model) handleConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
-	case isEnterKey(msg):
+	case m.keyIs(msg, config.ActionConfirm):
 		m.confirmModal.Hide()
 		return m.deleteConversation(m.pendingDeleteID)
-	case isEscapeKey(msg):
+	case m.keyIs(msg, config.ActionCancel):
 		m.confirmModal.Hide()
 		m.pendingDeleteID = ""
 		return m, nil
@@ -697,12 +1485,22 @@ func (m Model) deleteConversation(id string) (tea.Model, tea.Cmd) {
 
 	m.pendingDeleteID = ""
 
+	var cmds []tea.Cmd
+	if m.cacheEnabled {
+		m.cache.RemoveConversation(id)
+		cmds = append(cmds, m.scheduleCacheFlush())
+	}
+	if m.folderState.IsPinned(id) || m.folderState.IsMuted(id) {
+		m.folderState.Remove(id)
+		cmds = append(cmds, m.saveFolders())
+	}
+
 	// Delete on server
 	if m.client != nil && !urn.IsEmpty() {
-		return m, m.client.DeleteConversation(urn)
+		cmds = append(cmds, m.client.DeleteConversation(urn))
 	}
 
-	return m, nil
+	return m, tea.Batch(cmds...)
 }
 
 func (m Model) handleConversationDeleted(msg linkedin.ConversationDeletedMsg) (tea.Model, tea.Cmd) {
@@ -711,13 +1509,35 @@ func (m Model) handleConversationDeleted(msg linkedin.ConversationDeletedMsg) (t
 }
 
 func (m *Model) updateFilterCounts() {
-	unreadCount := 0
+	counts := m.folderCounts()
+	m.sidebar.SetCounts(counts...)
+	m.convList.SetFilterCounts(counts[sidebar.Inbox], counts[sidebar.Unread])
+	if m.folder == sidebar.Inbox || m.folder == sidebar.Unread {
+		m.convList.SetFolder("", 0)
+	} else {
+		m.convList.SetFolder(m.sidebar.SelectedFolder().Name, counts[m.folder])
+	}
+	m.header.SetUnread(m.totalUnread())
+}
+
+// totalUnread sums unread messages across all conversations, leaving out
+// muted ones so they don't show in the header or the terminal title.
+func (m Model) totalUnread() int {
+	total := 0
 	for _, dc := range m.conversations {
-		if dc.Unread {
-			unreadCount++
+		if !m.folderState.IsMuted(dc.ID) {
+			total += dc.UnreadMessages()
 		}
 	}
-	m.convList.SetFilterCounts(len(m.conversations), unreadCount)
+	return total
+}
+
+// windowTitle returns the terminal title, including the unread total.
+func (m Model) windowTitle() string {
+	if n := m.totalUnread(); n > 0 {
+		return fmt.Sprintf("Endorse (%d)", n)
+	}
+	return "Endorse"
 }
 
 func (m Model) openSelectedConversationAndReply() (tea.Model, tea.Cmd) {
@@ -750,17 +1570,234 @@ func (m Model) sendMessage() (tea.Model, tea.Cmd) {
 	}
 
 	convID := m.thread.ConversationID()
+	urn := m.findConversationURN(convID)
+	if urn.IsEmpty() {
+		return m, nil
+	}
+
+	// Queue before clearing compose so the text survives a failed send
+	item := m.outbox.Add(convID, urn, text, time.Now())
+	m.refreshUnsent()
 	m.compose.Reset()
+	m.stopTyping()
 	// Stay in compose focus — don't deactivate
 
-	if m.client != nil {
-		urn := m.findConversationURN(convID)
-		if !urn.IsEmpty() {
-			return m, m.client.SendMessage(urn, text)
+	return m, tea.Batch(m.sendOutboxItem(item.ID, false), m.saveOutbox())
+}
+
+// notifyTyping sends a typing notification for the open conversation,
+// throttled to typingSendInterval and subject to the privacy setting.
+func (m *Model) notifyTyping() tea.Cmd {
+	if m.client == nil || !m.cfg.Privacy.SendTyping {
+		return nil
+	}
+	convID := m.thread.ConversationID()
+	urn := m.findConversationURN(convID)
+	if urn.IsEmpty() {
+		return nil
+	}
+	now := time.Now()
+	if convID == m.typingConvID && now.Sub(m.typingSentAt) < typingSendInterval {
+		return nil
+	}
+	m.typingConvID = convID
+	m.typingSentAt = now
+	return m.client.StartTyping(urn)
+}
+
+// stopTyping ends the current typing run. LinkedIn has no explicit stop, so
+// this just stops refreshing; the next keystroke notifies straight away.
+func (m *Model) stopTyping() {
+	m.typingConvID = ""
+	m.typingSentAt = time.Time{}
+}
+
+// --- Outbox ---
+
+// sendOutboxItem marks a queued message as in flight and sends it. Results
+// come back as OutboxSentMsg or OutboxSendFailedMsg tagged with the item ID.
+func (m *Model) sendOutboxItem(id string, manual bool) tea.Cmd {
+	if m.client == nil || m.reauth {
+		return nil // resent once a validated client is available
+	}
+	item, ok := m.outbox.MarkSending(id, manual)
+	if !ok {
+		return nil
+	}
+	m.refreshUnsent()
+
+	send := m.client.SendMessage(item.ConversationURN, item.Text)
+	return m.forProfile(func() tea.Msg {
+		switch msg := send().(type) {
+		case linkedin.MessageSentMsg:
+			return OutboxSentMsg{ItemID: id, Sent: msg}
+		case linkedin.MessageSendFailedMsg:
+			return OutboxSendFailedMsg{ItemID: id, Err: msg.Err}
+		default:
+			return msg
 		}
+	})
+}
+
+// handleOutboxSent swaps the sending placeholder for the server's message.
+func (m Model) handleOutboxSent(msg OutboxSentMsg) (tea.Model, tea.Cmd) {
+	m.outbox.Remove(msg.ItemID)
+	if msg.Sent.ConversationID == m.thread.ConversationID() {
+		m.thread.ConfirmSent(msg.ItemID, toThreadMessage(msg.Sent.Message))
 	}
+	m.refreshUnsent()
+	return m, tea.Batch(m.cacheMessages(msg.Sent.ConversationID, msg.Sent.Message), m.saveOutbox())
+}
 
-	return m, nil
+func (m Model) handleOutboxSendFailed(msg OutboxSendFailedMsg) (tea.Model, tea.Cmd) {
+	item, ok := m.outbox.MarkFailed(msg.ItemID, msg.Err, time.Now())
+	if !ok {
+		return m, nil
+	}
+	m.refreshUnsent()
+
+	m.statusBar.SetError("Failed to send: " + msg.Err.Error())
+	cmds := []tea.Cmd{clearErrorAfter(), m.saveOutbox()}
+	if !item.Failed {
+		attempts := item.Attempts
+		cmds = append(cmds, tea.Tick(time.Until(item.NextAttempt), func(_ time.Time) tea.Msg {
+			return OutboxRetryMsg{ItemID: item.ID, Attempts: attempts}
+		}))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// retryUnsent immediately resends every unsent message in the open thread.
+func (m *Model) retryUnsent() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, item := range m.outbox.ForConversation(m.thread.ConversationID()) {
+		if !item.Sending {
+			cmds = append(cmds, m.sendOutboxItem(item.ID, true))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// discardUnsent drops the newest unsent message in the open thread.
+func (m *Model) discardUnsent() tea.Cmd {
+	items := m.outbox.ForConversation(m.thread.ConversationID())
+	for i := len(items) - 1; i >= 0; i-- {
+		if !items[i].Sending {
+			m.outbox.Remove(items[i].ID)
+			m.refreshUnsent()
+			return m.saveOutbox()
+		}
+	}
+	return nil
+}
+
+// refreshUnsent shows the open conversation's outbox entries in the thread
+// as placeholders with their delivery state.
+func (m *Model) refreshUnsent() {
+	convID := m.thread.ConversationID()
+	sender := m.ownSenderName(convID)
+
+	var unsent []thread.Message
+	for _, item := range m.outbox.ForConversation(convID) {
+		delivery := thread.Retrying
+		switch {
+		case item.Sending:
+			delivery = thread.Sending
+		case item.Failed:
+			delivery = thread.Failed
+		case item.Attempts == 0:
+			delivery = thread.Queued
+		}
+		unsent = append(unsent, thread.Message{
+			ID:       item.ID,
+			Sender:   sender,
+			Body:     item.Text,
+			IsOwn:    true,
+			Delivery: delivery,
+			Attempts: item.Attempts,
+		})
+	}
+	m.thread.SetUnsent(unsent)
+}
+
+// ownSenderName returns the name the server uses for the user's own messages
+// in a conversation, so unsent entries group under the same sender header.
+func (m Model) ownSenderName(convID string) string {
+	msgs := m.cache.ConversationMessages(convID)
+	for i := len(msgs) - 1; i >= 0; i-- {
+		if msgs[i].IsOwn {
+			return msgs[i].Sender
+		}
+	}
+	return "You"
+}
+
+// saveOutbox writes a copy of the outbox to disk off the update loop.
+func (m Model) saveOutbox() tea.Cmd {
+	if !m.outboxEnabled {
+		return nil
+	}
+	snap := m.outbox.Clone()
+	profile := config.ActiveProfile()
+	return m.outboxSaves.save(func() error {
+		return config.InProfile(profile, func() error { return outbox.Save(snap) })
+	})
+}
+
+// --- Cache ---
+
+// cacheMessages records messages for a conversation so they are searchable,
+// and schedules a flush to disk if the cache is enabled.
+func (m *Model) cacheMessages(convID string, msgs ...linkedin.DisplayMessage) tea.Cmd {
+	if len(msgs) == 0 {
+		return nil
+	}
+	m.cache.MergeMessages(convID, msgs)
+	if !m.cacheEnabled {
+		return nil
+	}
+	return m.scheduleCacheFlush()
+}
+
+// forProfile stamps the result of a client call with the current profile
+// generation, so it's dropped if the account is switched before it arrives.
+func (m Model) forProfile(cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	gen := m.profileGeneration
+	return func() tea.Msg {
+		return ProfileMsg{Generation: gen, Msg: cmd()}
+	}
+}
+
+// scheduleCacheFlush debounces cache writes using a generation counter.
+func (m *Model) scheduleCacheFlush() tea.Cmd {
+	m.cacheGeneration++
+	gen := m.cacheGeneration
+	return tea.Tick(cacheFlushDelay, func(_ time.Time) tea.Msg {
+		return CacheFlushMsg{Generation: gen}
+	})
+}
+
+// saveCache writes a pruned copy of the cache to disk off the update loop.
+func (m Model) saveCache() tea.Cmd {
+	snap := m.cache.Prune(cache.LimitsFromConfig(m.cfg.Cache), time.Now())
+	profile := config.ActiveProfile()
+	return m.cacheSaves.save(func() error {
+		return config.InProfile(profile, func() error { return cache.Save(snap) })
+	})
+}
+
+// toThreadMessage converts a display message into a thread row.
+func toThreadMessage(dm linkedin.DisplayMessage) thread.Message {
+	return thread.Message{
+		ID:        dm.ID,
+		Sender:    dm.Sender,
+		Body:      dm.Body,
+		Timestamp: dm.Timestamp,
+		IsOwn:     dm.IsOwn,
+	}
 }
 
 func (m Model) findConversationURN(id string) linkedingo.URN {
@@ -813,6 +1850,11 @@ func (m *Model) cycleFocusBackward() {
 
 func (m Model) availablePanels() []FocusedPanel {
 	switch m.dims.Mode {
+	case layout.ThreePanel:
+		if m.thread.HasConversation() {
+			return []FocusedPanel{FocusSidebar, FocusConvList, FocusThread, FocusCompose}
+		}
+		return []FocusedPanel{FocusSidebar, FocusConvList, FocusThread}
 	case layout.TwoPanel:
 		if m.thread.HasConversation() {
 			return []FocusedPanel{FocusConvList, FocusThread, FocusCompose}
@@ -824,14 +1866,25 @@ func (m Model) availablePanels() []FocusedPanel {
 }
 
 func (m *Model) setFocus(panel FocusedPanel) {
+	m.sidebar.Blur()
 	m.convList.Blur()
 	m.thread.Blur()
 	if panel != FocusCompose {
 		m.compose.Blur()
 	}
 
+	if panel != FocusCompose {
+		m.stopTyping()
+	}
+
 	m.focus = panel
+	m.statusBar.SetHints(m.hintsFor(panel))
+	if m.helpModal.Active() {
+		m.helpModal.SetCurrent(m.helpContext())
+	}
 	switch panel {
+	case FocusSidebar:
+		m.sidebar.Focus()
 	case FocusConvList:
 		m.convList.Focus()
 	case FocusThread:
@@ -846,6 +1899,7 @@ func (m *Model) updateSizes() {
 	m.statusBar.SetWidth(m.dims.Width)
 
 	contentH := m.dims.ContentHeight
+	m.sidebar.SetSize(m.dims.SidebarWidth, contentH)
 	m.convList.SetSize(m.dims.ConvListWidth, contentH)
 	m.thread.SetSize(m.dims.ThreadWidth, contentH)
 
@@ -866,8 +1920,13 @@ func (m Model) View() string {
 		return "Loading..."
 	}
 
-	// Auth state: show auth modal fullscreen
-	if m.state == StateAuth {
+	// The account switcher overlays everything, including the auth form
+	if m.profilesModal.Active() {
+		return m.profilesModal.View()
+	}
+
+	// Auth state or re-auth overlay: show auth modal fullscreen
+	if m.state == StateAuth || m.reauth {
 		return m.authModal.View()
 	}
 
@@ -878,6 +1937,16 @@ func (m Model) View() string {
 			m.styles.AccentText.Render("Connecting to LinkedIn..."))
 	}
 
+	// Help screen overlays everything
+	if m.helpModal.Active() {
+		return m.helpModal.View()
+	}
+
+	// Command palette overlays everything
+	if m.paletteModal.Active() {
+		return m.paletteModal.View()
+	}
+
 	// Confirm modal overlays everything
 	if m.confirmModal.Active() {
 		return m.confirmModal.View()
@@ -888,6 +1957,12 @@ func (m Model) View() string {
 
 	var contentView string
 	switch m.dims.Mode {
+	case layout.ThreePanel:
+		contentView = lipgloss.JoinHorizontal(lipgloss.Top,
+			m.sidebar.View(),
+			m.convList.View(),
+			m.thread.View(),
+		)
 	case layout.TwoPanel:
 		contentView = lipgloss.JoinHorizontal(lipgloss.Top,
 			m.convList.View(),
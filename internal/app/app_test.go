@@ -1,9 +1,26 @@
 package app
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"go.mau.fi/mautrix-linkedin/pkg/linkedingo"
+
+	"github.com/ggfevans/endorse/internal/config"
+	"github.com/ggfevans/endorse/internal/folders"
+	"github.com/ggfevans/endorse/internal/linkedin"
+	"github.com/ggfevans/endorse/internal/ui/modal"
+	"github.com/ggfevans/endorse/internal/ui/sidebar"
+	"github.com/ggfevans/endorse/internal/ui/styles"
+	"github.com/ggfevans/endorse/internal/ui/thread"
 )
 
 func TestNewModel(t *testing.T) {
@@ -97,3 +114,1151 @@ func TestFocusPanelConstants(t *testing.T) {
 		t.Error("expected 4 distinct FocusedPanel values")
 	}
 }
+
+func TestMergeConversations(t *testing.T) {
+	a := linkedin.DisplayConversation{ID: "a", Title: "A", URN: linkedingo.NewURN("urn:li:msg_conversation:a")}
+	b := linkedin.DisplayConversation{ID: "b", Title: "B", URN: linkedingo.NewURN("urn:li:msg_conversation:b")}
+	bUpdated := b
+	bUpdated.Title = "B updated"
+	c := linkedin.DisplayConversation{ID: "c", Title: "C", URN: linkedingo.NewURN("urn:li:msg_conversation:c")}
+
+	merged, added := mergeConversations([]linkedin.DisplayConversation{a, b}, []linkedin.DisplayConversation{bUpdated, c})
+
+	if added != 1 {
+		t.Errorf("expected 1 new conversation, got %d", added)
+	}
+	if len(merged) != 3 {
+		t.Fatalf("expected 3 conversations after merge, got %d", len(merged))
+	}
+	if merged[1].Title != "B updated" {
+		t.Errorf("expected duplicate URN to be updated in place, got %q", merged[1].Title)
+	}
+}
+
+func TestOutboxRetryAfterFailedSend(t *testing.T) {
+	m := New(Options{DemoMode: true})
+	urn := linkedingo.NewURN("urn:li:conversation:conv-karl")
+	m.conversations = []linkedin.DisplayConversation{{ID: urn.String(), Title: "Karl Havoc", URN: urn}}
+	m.thread.SetConversation(urn.String(), "Karl Havoc")
+
+	item := m.outbox.Add(urn.String(), urn, "hello?", time.Now())
+
+	result, cmd := m.Update(OutboxSendFailedMsg{ItemID: item.ID, Err: errors.New("network down")})
+	m = result.(Model)
+	if cmd == nil {
+		t.Fatal("expected a retry to be scheduled after a failed send")
+	}
+	got, ok := m.outbox.Get(item.ID)
+	if !ok || got.Attempts != 1 {
+		t.Fatalf("expected the message to stay queued with 1 attempt, got %+v (ok=%v)", got, ok)
+	}
+	if m.thread.UnsentCount() != 1 {
+		t.Errorf("expected the unsent message to show in the thread, got %d", m.thread.UnsentCount())
+	}
+
+	// A stale retry timer from an earlier attempt is ignored
+	if _, cmd := m.Update(OutboxRetryMsg{ItemID: item.ID, Attempts: 0}); cmd != nil {
+		t.Error("expected stale retry to be ignored")
+	}
+
+	result, cmd = m.Update(OutboxRetryMsg{ItemID: item.ID, Attempts: 1})
+	m = result.(Model)
+	if cmd == nil {
+		t.Fatal("expected the retry to send the message")
+	}
+	sent, ok := cmd().(ProfileMsg)
+	if _, isSent := sent.Msg.(OutboxSentMsg); !ok || !isSent {
+		t.Fatalf("expected OutboxSentMsg from the retry, got %#v", sent)
+	}
+
+	result, _ = m.Update(sent)
+	m = result.(Model)
+	if len(m.outbox.Items) != 0 {
+		t.Errorf("expected outbox to be empty after delivery, got %d items", len(m.outbox.Items))
+	}
+	if m.thread.UnsentCount() != 0 {
+		t.Errorf("expected no unsent rows after delivery, got %d", m.thread.UnsentCount())
+	}
+}
+
+func TestRealtimeEchoConfirmsOutboxEntry(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m := New(Options{DemoMode: true})
+	urn := linkedingo.NewURN("urn:li:conversation:conv-karl")
+	m.conversations = []linkedin.DisplayConversation{{ID: urn.String(), Title: "Karl Havoc", URN: urn}}
+	m.thread.SetConversation(urn.String(), "Karl Havoc")
+	item := m.outbox.Add(urn.String(), urn, "hello?", time.Now())
+	m.sendOutboxItem(item.ID, false)
+
+	echo := linkedin.DisplayMessage{ID: "urn:li:msg:echo", Sender: "You", Body: "hello?", Timestamp: time.Now(), IsOwn: true}
+	result, _ := m.Update(linkedin.RealtimeMessageMsg{ConversationID: urn.String(), Message: echo})
+	m = result.(Model)
+	if m.thread.UnsentCount() != 0 || len(m.outbox.Items) != 0 || m.thread.MessageCount() != 1 {
+		t.Fatalf("expected the echo to replace the pending entry, got %d unsent, %d queued, %d messages",
+			m.thread.UnsentCount(), len(m.outbox.Items), m.thread.MessageCount())
+	}
+
+	// The send result arriving afterwards doesn't add it again
+	result, _ = m.Update(OutboxSentMsg{ItemID: item.ID, Sent: linkedin.MessageSentMsg{ConversationID: urn.String(), Message: echo}})
+	m = result.(Model)
+	if m.thread.MessageCount() != 1 {
+		t.Errorf("expected the sent message once, got %d", m.thread.MessageCount())
+	}
+}
+
+func TestSendBeforeAuthValidated(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m := New(Options{DemoMode: true})
+	urn := linkedingo.NewURN("urn:li:conversation:conv-karl")
+	m.conversations = []linkedin.DisplayConversation{{ID: urn.String(), Title: "Karl Havoc", URN: urn}}
+	m.thread.SetConversation(urn.String(), "Karl Havoc")
+
+	// Browsing the cached inbox: no client until the session is validated
+	m.client = nil
+	m.compose.Focus()
+	m.compose, _ = m.compose.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("hello?")})
+	result, _ := m.sendMessage()
+	m = result.(Model)
+	if len(m.outbox.Items) != 1 || m.outbox.Items[0].Sending {
+		t.Fatalf("expected the message queued but not sending, got %+v", m.outbox.Items)
+	}
+	if m.thread.UnsentCount() != 1 {
+		t.Errorf("expected the queued message to show in the thread, got %d", m.thread.UnsentCount())
+	}
+
+	m.client = linkedin.NewDemoClient()
+	result, _ = m.update(linkedin.AuthValidatedMsg{Username: "Demo User"})
+	m = result.(Model)
+	if len(m.outbox.Items) != 1 || !m.outbox.Items[0].Sending {
+		t.Errorf("expected the queued message sent once signed in, got %+v", m.outbox.Items)
+	}
+}
+
+func TestFirstPageAfterWarmCache(t *testing.T) {
+	m := New(Options{DemoMode: true})
+	now := time.Now()
+	cached := []linkedin.DisplayConversation{
+		{ID: "a", Title: "Alice", URN: linkedingo.NewURN("urn:li:conversation:a"), LastActivityAt: now.Add(-time.Minute)},
+		{ID: "b", Title: "Bob", URN: linkedingo.NewURN("urn:li:conversation:b"), LastActivityAt: now.Add(-time.Hour)},
+	}
+	// Loaded from the cache before the server answers
+	m.conversations = append([]linkedin.DisplayConversation(nil), cached...)
+	m.applyConversationFilter()
+
+	// The first server page holds only conversations the cache already had
+	result, _ := m.update(linkedin.ConversationsLoadedMsg{Conversations: cached})
+	m = result.(Model)
+	if !m.convList.HasMore() {
+		t.Fatal("expected older conversations to be available after the first server page")
+	}
+	if cmd := m.loadOlderConversations(); cmd == nil {
+		t.Error("expected paging back to fetch older conversations")
+	}
+
+	// A later sync isn't a first page and leaves paging alone
+	result, _ = m.update(linkedin.ConversationsLoadedMsg{Conversations: cached[:1]})
+	m = result.(Model)
+	if !m.convList.LoadingMore() {
+		t.Error("expected the older page request to stay in flight")
+	}
+}
+
+func TestFullPageDropsConversationsGoneFromServer(t *testing.T) {
+	m := New(Options{DemoMode: true})
+	now := time.Now()
+	conv := func(id string, age time.Duration) linkedin.DisplayConversation {
+		return linkedin.DisplayConversation{ID: id, Title: id, URN: linkedingo.NewURN("urn:li:conversation:" + id), LastActivityAt: now.Add(-age)}
+	}
+	left := conv("left", time.Minute)    // deleted on another device
+	kept := conv("kept", time.Hour)      // still on the server
+	older := conv("older", 48*time.Hour) // beyond the first page
+	archived := conv("archived", time.Second)
+	archived.Archived = true
+	m.conversations = []linkedin.DisplayConversation{left, kept, older, archived}
+	m.cacheEnabled = true
+
+	// A sync of changes says nothing about what's missing
+	result, _ := m.update(linkedin.ConversationsLoadedMsg{Conversations: []linkedin.DisplayConversation{kept}})
+	m = result.(Model)
+	if len(m.conversations) != 4 {
+		t.Fatalf("expected a partial sync to keep every conversation, got %d", len(m.conversations))
+	}
+
+	result, _ = m.update(linkedin.ConversationsLoadedMsg{Conversations: []linkedin.DisplayConversation{conv("new", 0), kept}, Full: true})
+	m = result.(Model)
+	var ids []string
+	for _, dc := range m.conversations {
+		ids = append(ids, dc.ID)
+	}
+	if !slices.Equal(ids, []string{"new", "archived", "kept", "older"}) {
+		t.Errorf("expected the conversation missing from the first page dropped, got %v", ids)
+	}
+	if len(m.cache.Conversations) != 4 {
+		t.Errorf("expected the cache reconciled too, got %d conversations", len(m.cache.Conversations))
+	}
+}
+
+func TestReconnectDelay(t *testing.T) {
+	low := func() float64 { return 0 }
+	high := func() float64 { return 0.999999 }
+
+	tests := []struct {
+		attempt  int
+		min, max time.Duration
+	}{
+		{1, 500 * time.Millisecond, time.Second},
+		{2, time.Second, 2 * time.Second},
+		{4, 4 * time.Second, 8 * time.Second},
+		{20, time.Minute, 2 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := reconnectDelay(tt.attempt, low); got != tt.min {
+			t.Errorf("attempt %d: expected lower bound %v, got %v", tt.attempt, tt.min, got)
+		}
+		if got := reconnectDelay(tt.attempt, high); got < tt.min || got > tt.max {
+			t.Errorf("attempt %d: expected delay within [%v, %v], got %v", tt.attempt, tt.min, tt.max, got)
+		}
+	}
+}
+
+func TestReconnectSupervisor(t *testing.T) {
+	m := New(Options{DemoMode: true})
+
+	result, cmd := m.Update(linkedin.RealtimeDisconnectedMsg{Err: errors.New("stream reset")})
+	m = result.(Model)
+	if cmd == nil {
+		t.Fatal("expected a reconnect countdown after a disconnect")
+	}
+	if m.reconnect.attempt != 1 || m.statusBar.Notice() == "" {
+		t.Fatalf("expected attempt 1 with a countdown notice, got attempt %d, notice %q", m.reconnect.attempt, m.statusBar.Notice())
+	}
+
+	// Further disconnects while counting down don't stack attempts
+	result, cmd = m.Update(linkedin.RealtimeDisconnectedMsg{Err: errors.New("stream reset")})
+	m = result.(Model)
+	if cmd != nil || m.reconnect.attempt != 1 {
+		t.Errorf("expected duplicate disconnect to be ignored, got attempt %d", m.reconnect.attempt)
+	}
+
+	// Ticks from a superseded countdown are ignored
+	if _, cmd := m.Update(ReconnectTickMsg{Generation: m.reconnect.generation - 1}); cmd != nil {
+		t.Error("expected stale reconnect tick to be ignored")
+	}
+
+	result, cmd = m.Update(linkedin.RealtimeConnectedMsg{})
+	m = result.(Model)
+	if cmd == nil {
+		t.Error("expected a backfill after reconnecting")
+	}
+	if m.reconnect.pending() || m.statusBar.Notice() != "" {
+		t.Errorf("expected supervisor reset after reconnecting, got attempt %d, notice %q", m.reconnect.attempt, m.statusBar.Notice())
+	}
+}
+
+func TestBackfillMergesMissedMessages(t *testing.T) {
+	m := New(Options{DemoMode: true})
+	convID := "urn:li:conversation:conv-karl"
+	m.thread.SetConversation(convID, "Karl Havoc")
+
+	now := time.Now()
+	seen := linkedin.DisplayMessage{ID: "urn:li:msg:1", Body: "before the drop", Timestamp: now.Add(-2 * time.Minute)}
+	missed := linkedin.DisplayMessage{ID: "urn:li:msg:2", Body: "while offline", Timestamp: now.Add(-time.Minute)}
+	live := linkedin.DisplayMessage{ID: "urn:li:msg:3", Body: "after reconnect", Timestamp: now}
+
+	result, _ := m.Update(linkedin.MessagesLoadedMsg{ConversationID: convID, Messages: []linkedin.DisplayMessage{seen}, PrevCursor: "c1"})
+	m = result.(Model)
+	result, _ = m.Update(linkedin.RealtimeMessageMsg{ConversationID: convID, Message: live})
+	m = result.(Model)
+
+	m.backfillConvID = convID
+	result, _ = m.Update(linkedin.MessagesLoadedMsg{ConversationID: convID, Messages: []linkedin.DisplayMessage{seen, missed, live}})
+	m = result.(Model)
+
+	if got := m.thread.MessageCount(); got != 3 {
+		t.Errorf("expected 3 messages after backfill without duplicates, got %d", got)
+	}
+	if m.prevCursor != "c1" {
+		t.Errorf("expected backfill to leave the paging cursor alone, got %q", m.prevCursor)
+	}
+	if m.backfillConvID != "" {
+		t.Error("expected backfill to be cleared once merged")
+	}
+}
+
+func TestSearchHitOlderThanFirstPage(t *testing.T) {
+	m := New(Options{DemoMode: true})
+	result, _ := m.update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = result.(Model)
+	convID := "urn:li:conversation:conv-karl"
+	m.conversations = []linkedin.DisplayConversation{{ID: convID, URN: linkedingo.NewURN(convID), Title: "Karl Havoc"}}
+
+	now := time.Now()
+	hit := linkedin.DisplayMessage{ID: "urn:li:msg:old", Body: "the needle", Timestamp: now.Add(-48 * time.Hour)}
+	var page []linkedin.DisplayMessage
+	for i := range 20 {
+		page = append(page, linkedin.DisplayMessage{
+			ID:        fmt.Sprintf("urn:li:msg:%d", i),
+			Body:      fmt.Sprintf("recent %d", i),
+			Timestamp: now.Add(time.Duration(i-20) * time.Minute),
+		})
+	}
+
+	// The hit is cached: opening it shows the cached messages, and the
+	// newest page must not replace them
+	m.cacheMessages(convID, hit)
+	m.thread.SetConversation(convID, "Karl Havoc")
+	m.thread.JumpTo(hit.ID)
+	m.thread.SetMessages([]thread.Message{toThreadMessage(hit)})
+
+	result, cmd := m.update(linkedin.MessagesLoadedMsg{ConversationID: convID, Messages: page, PrevCursor: "c1"})
+	m = result.(Model)
+	if got := m.thread.MessageCount(); got != 21 {
+		t.Errorf("expected the newest page merged with the cached hit, got %d messages", got)
+	}
+	if m.thread.JumpPending() || !strings.Contains(ansiCodes.ReplaceAllString(m.thread.View(), ""), "the needle") {
+		t.Error("expected the view to stay on the cached hit")
+	}
+	if cmd != nil && m.thread.LoadingOlder() {
+		t.Error("expected no paging back once the hit is loaded")
+	}
+
+	// The hit isn't cached: keep paging back until it arrives
+	m = New(Options{DemoMode: true})
+	result, _ = m.update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = result.(Model)
+	m.conversations = []linkedin.DisplayConversation{{ID: convID, URN: linkedingo.NewURN(convID), Title: "Karl Havoc"}}
+	m.thread.SetConversation(convID, "Karl Havoc")
+	m.thread.JumpTo(hit.ID)
+
+	result, cmd = m.update(linkedin.MessagesLoadedMsg{ConversationID: convID, Messages: page, PrevCursor: "c1"})
+	m = result.(Model)
+	if cmd == nil || !m.thread.LoadingOlder() {
+		t.Fatal("expected an older page to be fetched while the hit isn't loaded")
+	}
+
+	result, _ = m.update(linkedin.MessagesLoadedMsg{ConversationID: convID, Messages: []linkedin.DisplayMessage{hit}, Older: true})
+	m = result.(Model)
+	if m.thread.JumpPending() {
+		t.Error("expected the hit to be loaded from the older page")
+	}
+	if !strings.Contains(ansiCodes.ReplaceAllString(m.thread.View(), ""), "the needle") {
+		t.Error("expected the view to scroll to the hit once it arrives")
+	}
+}
+
+func TestRealtimeMessageUpdatesConversationInPlace(t *testing.T) {
+	m := New(Options{DemoMode: true})
+	now := time.Now()
+	m.conversations = []linkedin.DisplayConversation{
+		{ID: "a", Title: "Alice", URN: linkedingo.NewURN("urn:li:conversation:a"), LastActivityAt: now.Add(-time.Minute)},
+		{ID: "b", Title: "Bob", URN: linkedingo.NewURN("urn:li:conversation:b"), LastActivityAt: now.Add(-time.Hour)},
+		{ID: "c", Title: "Carol", URN: linkedingo.NewURN("urn:li:conversation:c"), LastActivityAt: now.Add(-2 * time.Hour)},
+	}
+	m.applyConversationFilter()
+	m.convList.SelectID("b")
+
+	// Call update directly so the terminal title command doesn't mask a refetch
+	result, cmd := m.update(linkedin.RealtimeMessageMsg{
+		ConversationID: "c",
+		Message:        linkedin.DisplayMessage{ID: "urn:li:msg:9", Body: "ping", Timestamp: now},
+	})
+	m = result.(Model)
+
+	if cmd != nil {
+		t.Error("expected no refetch for a known conversation")
+	}
+	if m.conversations[0].ID != "c" || m.conversations[0].LastMessage != "ping" || !m.conversations[0].Unread {
+		t.Errorf("expected conversation c moved to the top with the new preview and unread, got %+v", m.conversations[0])
+	}
+	if sel, _ := m.convList.SelectedConversation(); sel.ID != "b" {
+		t.Errorf("expected selection to stay on conversation b, got %q", sel.ID)
+	}
+
+	// An unknown conversation falls back to a full refetch
+	_, cmd = m.update(linkedin.RealtimeMessageMsg{
+		ConversationID: "z",
+		Message:        linkedin.DisplayMessage{ID: "urn:li:msg:10", Body: "new thread", Timestamp: now},
+	})
+	if cmd == nil {
+		t.Error("expected a refetch for an unknown conversation")
+	}
+}
+
+func TestUnreadCountsRollUp(t *testing.T) {
+	m := New(Options{DemoMode: true})
+	m.conversations = []linkedin.DisplayConversation{
+		{ID: "a", Title: "Alice", Unread: true, UnreadCount: 2},
+		{ID: "b", Title: "Bob", Unread: true}, // marked unread by hand
+		{ID: "c", Title: "Carol"},
+	}
+	if got := m.totalUnread(); got != 3 {
+		t.Errorf("expected 3 unread messages, got %d", got)
+	}
+	if got := m.windowTitle(); got != "Endorse (3)" {
+		t.Errorf("expected unread total in title, got %q", got)
+	}
+
+	result, _ := m.update(linkedin.RealtimeMessageMsg{
+		ConversationID: "c",
+		Message:        linkedin.DisplayMessage{ID: "urn:li:msg:1", Body: "hi", Timestamp: time.Now()},
+	})
+	m = result.(Model)
+	if got := m.conversations[0].UnreadCount; m.conversations[0].ID != "c" || got != 1 {
+		t.Errorf("expected realtime message to bump c to 1 unread, got %d on %q", got, m.conversations[0].ID)
+	}
+
+	m.applyConversationFilter()
+	m.convList.SelectID("a")
+	result, _ = m.openSelectedConversation()
+	m = result.(Model)
+	for _, dc := range m.conversations {
+		if dc.ID == "a" && (dc.Unread || dc.UnreadCount != 0) {
+			t.Errorf("expected opening a conversation to reset its count, got %+v", dc)
+		}
+	}
+	if got := m.totalUnread(); got != 2 {
+		t.Errorf("expected 2 unread messages after reading a, got %d", got)
+	}
+
+	// Muted conversations still list as unread but leave the total
+	m.folderState.ToggleMuted("b")
+	if got := m.totalUnread(); got != 1 {
+		t.Errorf("expected the muted conversation left out of the total, got %d", got)
+	}
+	if got := m.folderCounts()[sidebar.Unread]; got != 2 {
+		t.Errorf("expected the muted conversation still in Unread, got %d", got)
+	}
+}
+
+func TestSidebarFolderDrivesFilter(t *testing.T) {
+	m := New(Options{DemoMode: true})
+	result, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = result.(Model)
+	m.state = StateMessaging
+
+	m.conversations = []linkedin.DisplayConversation{
+		{ID: "a", Title: "Alice", Unread: true},
+		{ID: "b", Title: "Bob"},
+		{ID: "c", Title: "Carol"},
+		{ID: "d", Title: "Dave", Archived: true},
+	}
+	m.folderState.TogglePinned("b")
+	m.folderState.ToggleMuted("c")
+	m.applyConversationFilter()
+	m.updateFilterCounts()
+	if got := m.convList.Count(); got != 3 {
+		t.Errorf("expected the archived conversation hidden from Inbox, got %d listed", got)
+	}
+
+	// Tab from the conversation list wraps round to the sidebar
+	for range 2 {
+		result, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+		m = result.(Model)
+	}
+	if m.focus != FocusSidebar || !m.sidebar.Focused() {
+		t.Fatalf("expected the sidebar in the Tab cycle, focus=%d", m.focus)
+	}
+
+	down := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}}
+	result, _ = m.Update(down) // Unread
+	m = result.(Model)
+	if sel, _ := m.convList.SelectedConversation(); m.convList.Count() != 1 || sel.ID != "a" {
+		t.Errorf("expected only the unread conversation, got %d listed", m.convList.Count())
+	}
+
+	result, _ = m.Update(down) // Pinned
+	m = result.(Model)
+	if sel, _ := m.convList.SelectedConversation(); m.convList.Count() != 1 || sel.ID != "b" {
+		t.Errorf("expected only the pinned conversation, got %d listed", m.convList.Count())
+	}
+
+	result, _ = m.Update(down) // Archived
+	m = result.(Model)
+	if sel, _ := m.convList.SelectedConversation(); m.convList.Count() != 1 || sel.ID != "d" {
+		t.Errorf("expected only the archived conversation, got %d listed", m.convList.Count())
+	}
+
+	result, _ = m.Update(down) // Muted
+	m = result.(Model)
+	if sel, _ := m.convList.SelectedConversation(); m.convList.Count() != 1 || sel.ID != "c" {
+		t.Errorf("expected only the muted conversation, got %d listed", m.convList.Count())
+	}
+
+	// Narrowing the terminal hides the sidebar and moves focus off it
+	result, _ = m.Update(tea.WindowSizeMsg{Width: 70, Height: 40})
+	m = result.(Model)
+	if m.focus == FocusSidebar {
+		t.Error("expected focus to leave the hidden sidebar")
+	}
+}
+
+func TestTypingNotificationsThrottled(t *testing.T) {
+	m := New(Options{DemoMode: true})
+	urn := linkedingo.NewURN("urn:li:conversation:conv-karl")
+	m.conversations = []linkedin.DisplayConversation{{ID: urn.String(), Title: "Karl Havoc", URN: urn}}
+	m.thread.SetConversation(urn.String(), "Karl Havoc")
+	m.cfg.Privacy.SendTyping = true
+
+	if m.notifyTyping() == nil {
+		t.Fatal("expected the first keystroke to send a typing notification")
+	}
+	if m.notifyTyping() != nil {
+		t.Error("expected keystrokes within the interval to be throttled")
+	}
+
+	m.typingSentAt = time.Now().Add(-typingSendInterval)
+	if m.notifyTyping() == nil {
+		t.Error("expected a refresh once the interval has passed")
+	}
+
+	// Leaving compose ends the run, so typing again notifies immediately
+	m.setFocus(FocusThread)
+	if m.notifyTyping() == nil {
+		t.Error("expected a notification after blurring and resuming")
+	}
+
+	m.stopTyping()
+	m.cfg.Privacy.SendTyping = false
+	if m.notifyTyping() != nil {
+		t.Error("expected no notifications when disabled in config")
+	}
+}
+
+func TestSeenReceiptsShownInThread(t *testing.T) {
+	m := New(Options{DemoMode: true})
+	result, _ := m.update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = result.(Model)
+	convID := "urn:li:conversation:conv-team"
+	m.conversations = []linkedin.DisplayConversation{{
+		ID:    convID,
+		Title: "Team",
+		Participants: []linkedin.DisplayParticipant{
+			{Name: "Karl Havoc", URN: linkedingo.NewURN("urn:li:fsd_profile:karl")},
+		},
+	}}
+	m.thread.SetConversation(convID, "Team")
+
+	now := time.Now()
+	mine := linkedin.DisplayMessage{ID: "urn:li:msg:1", Body: "ship it?", Timestamp: now.Add(-3 * time.Minute), IsOwn: true}
+	result, _ = m.update(linkedin.MessagesLoadedMsg{ConversationID: convID, Messages: []linkedin.DisplayMessage{mine}})
+	m = result.(Model)
+
+	result, _ = m.update(linkedin.RealtimeSeenMsg{
+		ConversationID: convID,
+		MessageID:      mine.ID,
+		ReaderURN:      linkedingo.NewURN("urn:li:fsd_profile:karl"),
+		SeenAt:         now.Add(-2 * time.Minute),
+	})
+	m = result.(Model)
+
+	if out := m.thread.View(); !strings.Contains(out, "Seen by Karl Havoc") {
+		t.Errorf("expected reader resolved from participants, got:\n%s", out)
+	}
+
+	// A receipt from our own account marks the conversation read instead
+	m.conversations[0].Unread = true
+	m.conversations[0].UnreadCount = 2
+	result, _ = m.update(linkedin.RealtimeSeenMsg{ConversationID: convID, MessageID: mine.ID, IsOwn: true})
+	m = result.(Model)
+	if m.conversations[0].Unread || m.conversations[0].UnreadCount != 0 {
+		t.Error("expected an own receipt to clear unread state")
+	}
+	if len(m.receiptsFor(convID)) != 1 {
+		t.Errorf("expected own receipts not to be recorded, got %d", len(m.receiptsFor(convID)))
+	}
+}
+
+func TestTimestampToggleAndHover(t *testing.T) {
+	m := New(Options{DemoMode: true})
+	m.cfg.Display.AbsoluteTimes = false
+	result, _ := m.update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = result.(Model)
+	m.state = StateMessaging
+	convID := "urn:li:conversation:conv-karl"
+	m.thread.SetConversation(convID, "Karl Havoc")
+	msg := linkedin.DisplayMessage{ID: "urn:li:msg:1", Sender: "Karl Havoc", Body: "hello", Timestamp: time.Now().Add(-time.Hour)}
+	result, _ = m.update(linkedin.MessagesLoadedMsg{ConversationID: convID, Messages: []linkedin.DisplayMessage{msg}})
+	m = result.(Model)
+
+	if _, cmd := m.update(ClockTickMsg{}); cmd == nil {
+		t.Error("expected the clock tick to reschedule itself")
+	}
+
+	m.setFocus(FocusConvList)
+	result, _ = m.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	m = result.(Model)
+	if !m.cfg.Display.AbsoluteTimes {
+		t.Error("expected t to switch to absolute timestamps")
+	}
+
+	// The thread viewport starts below the header, border and title; the
+	// message is the last one, so its lines run to the bottom row
+	x := m.dims.SidebarWidth + m.dims.ConvListWidth + 2
+	y := 3 + m.thread.VisibleHeight() - 1
+	result, _ = m.update(tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionMotion})
+	m = result.(Model)
+	if m.thread.SelectedMessage() != msg.ID {
+		t.Errorf("expected hovering the message to select it, got %q", m.thread.SelectedMessage())
+	}
+
+	// On a narrow terminal the thread fills the width, from the first column
+	m = New(Options{DemoMode: true})
+	result, _ = m.update(tea.WindowSizeMsg{Width: 50, Height: 40})
+	m = result.(Model)
+	m.state = StateMessaging
+	m.thread.SetConversation(convID, "Karl Havoc")
+	result, _ = m.update(linkedin.MessagesLoadedMsg{ConversationID: convID, Messages: []linkedin.DisplayMessage{msg}})
+	m = result.(Model)
+	result, _ = m.update(tea.MouseMsg{X: 2, Y: 3 + m.thread.VisibleHeight() - 1, Action: tea.MouseActionMotion})
+	m = result.(Model)
+	if m.thread.SelectedMessage() != msg.ID {
+		t.Errorf("expected hovering in a single-panel layout to select the message, got %q", m.thread.SelectedMessage())
+	}
+}
+
+func TestExportSelectedConversation(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m := New(Options{DemoMode: true})
+	result, _ := m.update(linkedin.ConversationsLoadedMsg{Conversations: []linkedin.DisplayConversation{{
+		ID:    "urn:li:conversation:conv-karl",
+		Title: "Karl Havoc",
+		URN:   linkedingo.NewURN("urn:li:conversation:conv-karl"),
+	}}})
+	m = result.(Model)
+	m.setFocus(FocusConvList)
+
+	result, cmd := m.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	m = result.(Model)
+	if cmd == nil {
+		t.Fatal("expected e to start an export")
+	}
+	done, ok := cmd().(ExportDoneMsg)
+	if !ok || done.Err != nil {
+		t.Fatalf("expected a finished export, got %#v", done)
+	}
+	if !strings.HasSuffix(done.Path, ".md") || !strings.Contains(done.Path, filepath.Join(".config", "endorse", "exports", "karl-havoc-")) {
+		t.Errorf("unexpected export path %q", done.Path)
+	}
+	data, err := os.ReadFile(done.Path)
+	if err != nil || !strings.Contains(string(data), "Do you think they know I'm just a guy?") {
+		t.Errorf("expected the demo history in the transcript, got %v:\n%s", err, data)
+	}
+
+	result, _ = m.update(done)
+	m = result.(Model)
+	if !strings.Contains(m.statusBar.Notice(), done.Path) {
+		t.Errorf("expected the path in the status bar, got %q", m.statusBar.Notice())
+	}
+}
+
+func TestSwitchProfileKeepsStateSeparate(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Cleanup(func() { _ = config.SetProfile(config.DefaultProfile) })
+
+	var pinned folders.State
+	pinned.TogglePinned("a")
+	if err := folders.Save(pinned); err != nil {
+		t.Fatal(err)
+	}
+
+	m := New(Options{})
+	result, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = result.(Model)
+	if m.state != StateAuth || !m.folderState.IsPinned("a") {
+		t.Fatalf("expected the default profile's folders at the auth prompt, state=%d", m.state)
+	}
+
+	// Esc at the auth prompt opens the switcher; add a "work" profile
+	result, _ = m.update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = result.(Model)
+	if !m.profilesModal.Active() {
+		t.Fatal("expected Esc at the auth prompt to open the account switcher")
+	}
+	var cmd tea.Cmd
+	for _, key := range []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune("n")},
+		{Type: tea.KeyRunes, Runes: []rune("work")},
+		{Type: tea.KeyEnter},
+	} {
+		result, cmd = m.update(key)
+		m = result.(Model)
+	}
+	if cmd == nil {
+		t.Fatal("expected a profile selection")
+	}
+	result, _ = m.update(cmd())
+	m = result.(Model)
+
+	if config.ActiveProfile() != "work" || m.folderState.IsPinned("a") {
+		t.Fatalf("expected the work profile with its own folders, got %q %+v", config.ActiveProfile(), m.folderState)
+	}
+	if !strings.Contains(m.header.View(), "[work]") {
+		t.Errorf("expected the header to name the profile, got %q", m.header.View())
+	}
+	if _, err := os.Stat(filepath.Join(os.Getenv("HOME"), ".config", "endorse", "profiles", "work")); err != nil {
+		t.Errorf("expected a directory for the new profile: %v", err)
+	}
+
+	m.folderState.TogglePinned("b")
+	m.saveFolders()()
+
+	result, _ = m.update(modal.ProfileSelectedMsg{Name: config.DefaultProfile})
+	m = result.(Model)
+	if !m.folderState.IsPinned("a") || m.folderState.IsPinned("b") {
+		t.Errorf("expected the default profile's folders back, got %+v", m.folderState)
+	}
+}
+
+func TestSwitchProfileDropsInFlightResults(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Cleanup(func() { _ = config.SetProfile(config.DefaultProfile) })
+
+	m := New(Options{})
+	result, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = result.(Model)
+
+	loaded := func() tea.Msg {
+		return linkedin.ConversationsLoadedMsg{Conversations: []linkedin.DisplayConversation{{ID: "old-account", Title: "Old"}}}
+	}
+	validated := func() tea.Msg {
+		return linkedin.AuthValidatedMsg{Username: "Old Account"}
+	}
+	staleLoaded, staleValidated := m.forProfile(loaded), m.forProfile(validated)
+
+	result, _ = m.update(modal.ProfileSelectedMsg{Name: "work", New: true})
+	m = result.(Model)
+	if config.ActiveProfile() != "work" {
+		t.Fatalf("expected the work profile, got %q", config.ActiveProfile())
+	}
+
+	for _, cmd := range []tea.Cmd{staleLoaded, staleValidated} {
+		result, _ = m.update(cmd())
+		m = result.(Model)
+	}
+	if len(m.conversations) != 0 || m.username != "" || m.state != StateAuth {
+		t.Errorf("expected results for the old account dropped, got %d conversations, user %q, state %d", len(m.conversations), m.username, m.state)
+	}
+
+	result, _ = m.update(m.forProfile(loaded)())
+	m = result.(Model)
+	if len(m.conversations) != 1 {
+		t.Errorf("expected results for the current account applied, got %d conversations", len(m.conversations))
+	}
+}
+
+func TestClientResultsStampedWithProfile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m := New(Options{DemoMode: true})
+	urn := linkedingo.NewURN("urn:li:conversation:conv-karl")
+	m.conversations = []linkedin.DisplayConversation{{ID: urn.String(), Title: "Karl Havoc", URN: urn, Unread: true}}
+	m.thread.SetConversation(urn.String(), "Karl Havoc")
+	item := m.outbox.Add(urn.String(), urn, "hello?", time.Now())
+
+	m.reconnect.at = time.Now().Add(-time.Second)
+	cmds := map[string]tea.Cmd{
+		"send":      m.sendOutboxItem(item.ID, false),
+		"mark read": m.markCurrentConversationRead(),
+		"reconnect": m.handleReconnectTick(ReconnectTickMsg{Generation: m.reconnect.generation}),
+	}
+	for name, cmd := range cmds {
+		if cmd == nil {
+			t.Errorf("%s: expected a command", name)
+			continue
+		}
+		got := cmd()
+		if msg, ok := got.(ProfileMsg); !ok || msg.Generation != m.profileGeneration {
+			t.Errorf("%s: expected the result stamped with the profile, got %#v", name, got)
+		}
+	}
+}
+
+func TestUnlockEncryptedCredentials(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Cleanup(func() {
+		_ = config.UseCredentialBackend(config.CredentialsConfig{})
+		config.SetPassphrase("")
+	})
+
+	dir := filepath.Join(home, ".config", "endorse")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.toml"), []byte("[credentials]\nbackend = \"encrypted\"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	_ = config.UseCredentialBackend(config.CredentialsConfig{Backend: config.BackendEncrypted})
+	config.SetPassphrase("hunter2")
+	if err := config.SaveCredentials(config.Credentials{Cookie: "li_at=abc"}); err != nil {
+		t.Fatal(err)
+	}
+	config.SetPassphrase("")
+
+	m := New(Options{})
+	if m.state != StateAuth || m.authModal.PassphraseMode() != modal.PassphraseUnlock {
+		t.Fatalf("expected the unlock prompt, state=%d mode=%d", m.state, m.authModal.PassphraseMode())
+	}
+
+	result, _ := m.update(modal.PassphraseSubmitMsg{Passphrase: "wrong"})
+	m = result.(Model)
+	if m.state != StateAuth || !strings.Contains(m.authModal.View(), "Wrong passphrase") {
+		t.Fatalf("expected a wrong passphrase error, state=%d", m.state)
+	}
+
+	result, cmd := m.update(modal.PassphraseSubmitMsg{Passphrase: "hunter2"})
+	m = result.(Model)
+	if m.state != StateLoading || cmd == nil {
+		t.Errorf("expected validation to start after unlocking, state=%d", m.state)
+	}
+	if m.authModal.PassphraseMode() != modal.PassphraseOff {
+		t.Errorf("expected a later sign-in to reuse the passphrase, mode=%d", m.authModal.PassphraseMode())
+	}
+}
+
+func TestAuthImportValidatesBeforeSaving(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	m := New(Options{})
+	result, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = result.(Model)
+
+	result, _ = m.update(modal.AuthImportMsg{Path: "~/missing.har"})
+	m = result.(Model)
+	if !strings.Contains(m.authModal.View(), "Import failed") {
+		t.Error("expected an import error for a missing file")
+	}
+
+	path := filepath.Join(home, "cookies.txt")
+	cookiesTxt := "#HttpOnly_.www.linkedin.com\tTRUE\t/\tTRUE\t0\tli_at\tAQEDtoken\n"
+	if err := os.WriteFile(path, []byte(cookiesTxt), 0600); err != nil {
+		t.Fatal(err)
+	}
+	result, cmd := m.update(modal.AuthImportMsg{Path: "~/cookies.txt"})
+	m = result.(Model)
+	if cmd == nil || m.pendingCreds == nil || m.pendingCreds.Cookie != "li_at=AQEDtoken" {
+		t.Fatalf("expected the imported session to be validated, pending=%+v", m.pendingCreds)
+	}
+	if creds, _ := config.LoadCredentials(); !creds.IsEmpty() {
+		t.Error("expected nothing saved until the session validates")
+	}
+}
+
+func TestSessionExpiryKeepsInbox(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	m := New(Options{})
+	result, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = result.(Model)
+
+	self := linkedingo.NewURN("urn:li:fsd_profile:me")
+	urn := linkedingo.NewURN("urn:li:conversation:conv-karl")
+	m.state = StateMessaging
+	m.username, m.userURN = "Me", self
+	m.conversations = []linkedin.DisplayConversation{{ID: urn.String(), Title: "Karl Havoc", URN: urn}}
+	m.thread.SetConversation(urn.String(), "Karl Havoc")
+	m.compose.Focus()
+	m.compose, _ = m.compose.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("half a thought")})
+	item := m.outbox.Add(urn.String(), urn, "queued while signed out", time.Now())
+
+	result, _ = m.update(linkedin.SessionExpiredMsg{})
+	m = result.(Model)
+	if m.state != StateMessaging || !m.reauth {
+		t.Fatalf("expected the re-auth overlay over the inbox, state=%d reauth=%v", m.state, m.reauth)
+	}
+	if !strings.Contains(m.View(), "Session expired") {
+		t.Error("expected the auth form to be shown")
+	}
+
+	// Retries while signed out wait for the new session
+	if _, cmd := m.update(OutboxRetryMsg{ItemID: item.ID}); cmd != nil {
+		t.Error("expected no send without a session")
+	}
+
+	// Another account's session doesn't take over this inbox
+	other := linkedin.AuthValidatedMsg{Username: "Someone Else", UserURN: linkedingo.NewURN("urn:li:fsd_profile:other")}
+	m.pendingCreds = &config.Credentials{Cookie: "li_at=other"}
+	result, _ = m.update(other)
+	m = result.(Model)
+	if !m.reauth || m.username != "Me" {
+		t.Fatalf("expected a different account to be refused, reauth=%v user=%q", m.reauth, m.username)
+	}
+	if creds, _ := config.LoadCredentials(); !creds.IsEmpty() {
+		t.Error("expected the other account's session not to be saved")
+	}
+
+	m.pendingCreds = &config.Credentials{Cookie: "li_at=fresh"}
+	result, cmd := m.update(linkedin.AuthValidatedMsg{Username: "Me", UserURN: self})
+	m = result.(Model)
+	if m.reauth || m.client == nil || cmd == nil {
+		t.Fatalf("expected the client rebuilt and realtime resumed, reauth=%v", m.reauth)
+	}
+	if m.thread.ConversationID() != urn.String() || m.compose.Value() != "half a thought" {
+		t.Errorf("expected the open thread and draft kept, got %q %q", m.thread.ConversationID(), m.compose.Value())
+	}
+	if got, _ := m.outbox.Get(item.ID); !got.Sending {
+		t.Error("expected the queued message to be resent")
+	}
+	if creds, _ := config.LoadCredentials(); creds.Cookie != "li_at=fresh" {
+		t.Errorf("expected the new session saved, got %+v", creds)
+	}
+}
+
+func TestCycleTheme(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := filepath.Join(home, ".config", "endorse", "themes")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "broken.toml"), []byte("accent = \"purple\"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	m := New(Options{DemoMode: true, ThemeName: "github-light"})
+	if m.theme != config.GitHubLight {
+		t.Fatalf("expected the theme from the options, got %q", m.theme.Name)
+	}
+
+	// Past the last built-in theme, the broken file is skipped
+	result, _ := m.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("T")})
+	m = result.(Model)
+	if m.theme != config.Dracula || m.styles.Theme.Name != "dracula" {
+		t.Errorf("expected to wrap round to dracula, got %q", m.theme.Name)
+	}
+	if !strings.Contains(m.statusBar.Error(), "broken") {
+		t.Errorf("expected the broken theme file reported, got %q", m.statusBar.Error())
+	}
+	if m.statusBar.Notice() != "Theme: dracula" {
+		t.Errorf("expected the new theme named, got %q", m.statusBar.Notice())
+	}
+
+	result, _ = m.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("T")})
+	m = result.(Model)
+	if m.theme != config.Nord || m.styles.Theme.Name != "nord" {
+		t.Errorf("expected nord next, got %q", m.theme.Name)
+	}
+}
+
+func TestUnknownThemeFallsBack(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	m := New(Options{DemoMode: true, ThemeName: "vaporwave"})
+	if m.theme != styles.DefaultTheme(m.terminal) || !strings.Contains(m.statusBar.Error(), "vaporwave") {
+		t.Errorf("expected the default theme and an error, got %q %q", m.theme.Name, m.statusBar.Error())
+	}
+}
+
+func TestHighContrastOption(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	m := New(Options{DemoMode: true, ThemeName: "nord", HighContrast: true})
+	if !m.terminal.HighContrast || m.styles.Theme.Accent != "" {
+		t.Fatalf("expected colorless styles, got accent %q", m.styles.Theme.Accent)
+	}
+
+	// Switching theme keeps high contrast
+	result, _ := m.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("T")})
+	m = result.(Model)
+	if m.theme.Name != "gruvbox" || m.styles.Theme.Accent != "" {
+		t.Errorf("expected gruvbox without colors, got %q accent %q", m.theme.Name, m.styles.Theme.Accent)
+	}
+}
+
+func TestKeymapFromConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := filepath.Join(home, ".config", "endorse")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	writeConfig := func(toml string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, "config.toml"), []byte(toml), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeConfig("[keys]\nsearch = [\"ctrl+f\"]\nfilter = []\n")
+	m := New(Options{DemoMode: true})
+	result, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = result.(Model)
+
+	view := m.statusBar.View()
+	if !strings.Contains(view, "Ctrl+F") || strings.Contains(view, "Filter") {
+		t.Errorf("expected hints from the keymap, got %q", view)
+	}
+	result, _ = m.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	m = result.(Model)
+	if m.convList.Searching() {
+		t.Error("expected / to do nothing once search is rebound")
+	}
+	result, _ = m.update(tea.KeyMsg{Type: tea.KeyCtrlF})
+	m = result.(Model)
+	if !m.convList.Searching() {
+		t.Error("expected ctrl+f to start a search")
+	}
+
+	// A conflict is reported and the defaults are used instead
+	writeConfig("[keys]\ndelete = [\"m\"]\n")
+	m = New(Options{DemoMode: true})
+	if !strings.Contains(m.statusBar.Error(), `"m" is bound to both`) || !m.cfg.Keys.Matches(config.ActionDelete, "d") {
+		t.Errorf("expected the conflict reported with default keys, got %q", m.statusBar.Error())
+	}
+}
+
+// ansiCodes matches the styling escapes in rendered views.
+var ansiCodes = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func TestHelpScreen(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m := New(Options{DemoMode: true})
+	m.cfg.Keys[config.ActionDelete] = []string{"x", "delete"}
+	result, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 60})
+	m = result.(Model)
+	m.state = StateMessaging
+	m.setFocus(FocusConvList)
+
+	press := func(key tea.KeyMsg) {
+		t.Helper()
+		result, _ := m.update(key)
+		m = result.(Model)
+	}
+	question := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")}
+
+	press(question)
+	if !m.helpModal.Active() {
+		t.Fatal("expected ? to open the help screen")
+	}
+	view := ansiCodes.ReplaceAllString(m.View(), "")
+	for _, want := range []string{"Key Bindings", "Conversation list (current)", "x/Del", "Delete conversation", "Compose", "Confirm dialog"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in the help screen", want)
+		}
+	}
+	if strings.Index(view, "Conversation list") > strings.Index(view, "General") {
+		t.Error("expected the focused panel's section first")
+	}
+	press(question)
+	if m.helpModal.Active() {
+		t.Fatal("expected ? to close the help screen")
+	}
+
+	// It follows focus, and scrolls when it doesn't fit
+	m.setFocus(FocusThread)
+	result, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: 20})
+	m = result.(Model)
+	press(question)
+	view = ansiCodes.ReplaceAllString(m.View(), "")
+	if !strings.Contains(view, "Thread (current)") || !strings.Contains(view, "more below") {
+		t.Errorf("expected the thread section first with more below, got:\n%s", view)
+	}
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("G")})
+	if view = ansiCodes.ReplaceAllString(m.View(), ""); !strings.Contains(view, "more above") || strings.Contains(view, "more above and below") {
+		t.Errorf("expected to be scrolled to the end, got:\n%s", view)
+	}
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.helpModal.Active() || m.quitting {
+		t.Error("expected Esc to close the help screen without quitting")
+	}
+
+	// While composing, ? is text
+	m.setFocus(FocusCompose)
+	press(question)
+	if m.helpModal.Active() {
+		t.Error("expected ? to be typed while composing")
+	}
+}
+
+func TestCommandPalette(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m := New(Options{DemoMode: true})
+	result, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = result.(Model)
+	m.state = StateMessaging
+	m.conversations = []linkedin.DisplayConversation{
+		{ID: "a", Title: "Bob Smith", Unread: true},
+		{ID: "b", Title: "Hiring sync", Participants: []linkedin.DisplayParticipant{
+			{Name: "Carol White"},
+			{Name: "You", IsOwnUser: true},
+		}},
+	}
+	m.selectFolder(sidebar.Unread)
+	m.setFocus(FocusConvList)
+
+	press := func(key tea.KeyMsg) {
+		t.Helper()
+		result, _ := m.update(key)
+		m = result.(Model)
+	}
+	pick := func() {
+		t.Helper()
+		result, cmd := m.update(tea.KeyMsg{Type: tea.KeyEnter})
+		m = result.(Model)
+		if cmd == nil {
+			t.Fatal("expected Enter to pick an entry")
+		}
+		result, _ = m.update(cmd())
+		m = result.(Model)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyCtrlP})
+	if !m.paletteModal.Active() {
+		t.Fatal("expected ctrl+p to open the palette")
+	}
+	for _, r := range "crlwh" {
+		press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	if view := ansiCodes.ReplaceAllString(m.View(), ""); !strings.Contains(view, "Hiring sync  Carol White") {
+		t.Errorf("expected a participant to match fuzzily, got:\n%s", view)
+	}
+
+	// Picking a conversation outside the folder switches folder to open it
+	pick()
+	if m.paletteModal.Active() || m.thread.ConversationID() != "b" || m.folder != sidebar.Inbox {
+		t.Errorf("expected conversation b opened in the inbox, got %q in folder %d", m.thread.ConversationID(), m.folder)
+	}
+
+	// Actions run as their keys would
+	m.setFocus(FocusConvList)
+	press(tea.KeyMsg{Type: tea.KeyCtrlP})
+	for _, r := range "mark read" {
+		press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	pick()
+	if i := m.conversationIndex("b"); !m.conversations[i].Unread {
+		t.Error("expected mark read to mark conversation b unread again")
+	}
+
+	// Recent picks come first, most recent first
+	items := m.paletteItems("")
+	if len(items) < 2 || items[0].ID != "action:mark_read" || items[1].ID != "conversation:b" || !items[1].Recent {
+		t.Errorf("expected recent picks first, got %+v", items[:min(len(items), 2)])
+	}
+	if items := m.paletteItems("zzz"); len(items) != 0 {
+		t.Errorf("expected no matches, got %+v", items)
+	}
+}
+
+func TestSaveQueueDropsStaleSnapshots(t *testing.T) {
+	var q saveQueue
+	var written []string
+	write := func(s string) func() error {
+		return func() error { written = append(written, s); return nil }
+	}
+
+	older := q.save(write("one item"))
+	newer := q.save(write("empty"))
+	// The commands run on their own goroutines, in no particular order
+	newer()
+	older()
+	if !slices.Equal(written, []string{"empty"}) {
+		t.Errorf("expected the stale snapshot dropped, got %q", written)
+	}
+
+	q.saveNow(write("on quit"))
+	if !slices.Equal(written, []string{"empty", "on quit"}) {
+		t.Errorf("expected a synchronous save after the queued ones, got %q", written)
+	}
+}
@@ -0,0 +1,102 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ggfevans/endorse/internal/config"
+	"github.com/ggfevans/endorse/internal/export"
+	"github.com/ggfevans/endorse/internal/linkedin"
+)
+
+// exportConversation writes the full history of the open conversation, or
+// the selected one in the list, to the export directory.
+func (m *Model) exportConversation() tea.Cmd {
+	id := m.thread.ConversationID()
+	if m.focus == FocusConvList || id == "" {
+		conv, ok := m.convList.SelectedConversation()
+		if !ok {
+			return nil
+		}
+		id = conv.ID
+	}
+	i := m.conversationIndex(id)
+	if i < 0 || m.client == nil {
+		return nil
+	}
+	dc := m.conversations[i]
+
+	format, err := export.ParseFormat(m.cfg.Export.Format)
+	if err != nil {
+		m.statusBar.SetError(err.Error())
+		return clearErrorAfter()
+	}
+	dir, err := exportDir(m.cfg.Export.Dir)
+	if err != nil {
+		m.statusBar.SetError("Export failed: " + err.Error())
+		return clearErrorAfter()
+	}
+
+	if !m.reconnect.pending() {
+		m.statusBar.SetNotice("Exporting " + dc.Title + "…")
+	}
+	client := m.client
+	return func() tea.Msg {
+		msgs, err := export.History(linkedin.NewSync(client), dc.URN, time.Time{})
+		if err != nil {
+			return ExportDoneMsg{Err: err}
+		}
+		path := filepath.Join(dir, export.FileName(dc.Title, time.Now(), format))
+		err = export.WriteFile(path, format, []export.Transcript{{Conversation: dc, Messages: msgs}})
+		return ExportDoneMsg{Path: path, Err: err}
+	}
+}
+
+// handleExportDone reports where an export was written. The reconnect
+// countdown takes precedence over export notices.
+func (m *Model) handleExportDone(msg ExportDoneMsg) tea.Cmd {
+	pending := m.reconnect.pending()
+	if !pending {
+		m.statusBar.SetNotice("")
+	}
+	if msg.Err != nil {
+		m.statusBar.SetError("Export failed: " + msg.Err.Error())
+		return clearErrorAfter()
+	}
+	if pending {
+		return nil
+	}
+	notice := "Exported to " + msg.Path
+	m.statusBar.SetNotice(notice)
+	return clearNoticeAfter(notice)
+}
+
+// exportDir resolves the configured export directory, expanding a leading
+// "~/" and defaulting to the exports folder in the config directory.
+func exportDir(configured string) (string, error) {
+	if configured == "" {
+		dir, err := config.ConfigDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(dir, "exports"), nil
+	}
+	return expandHome(configured)
+}
+
+// expandHome replaces a leading "~/" in a path typed by the user with their
+// home directory.
+func expandHome(path string) (string, error) {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, rest), nil
+	}
+	return path, nil
+}
@@ -0,0 +1,111 @@
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ggfevans/endorse/internal/config"
+	"github.com/ggfevans/endorse/internal/folders"
+	"github.com/ggfevans/endorse/internal/linkedin"
+	"github.com/ggfevans/endorse/internal/ui/sidebar"
+)
+
+// inFolder reports whether a conversation belongs in the given sidebar
+// folder. Archived conversations only appear in the Archived folder.
+func (m Model) inFolder(dc linkedin.DisplayConversation, folder int) bool {
+	switch folder {
+	case sidebar.Unread:
+		return dc.Unread && !dc.Archived
+	case sidebar.Pinned:
+		return m.folderState.IsPinned(dc.ID)
+	case sidebar.Archived:
+		return dc.Archived
+	case sidebar.Muted:
+		return m.folderState.IsMuted(dc.ID)
+	default:
+		return !dc.Archived
+	}
+}
+
+// folderCounts returns the number of conversations in each folder, in
+// sidebar order.
+func (m Model) folderCounts() []int {
+	counts := make([]int, sidebar.Muted+1)
+	for _, dc := range m.conversations {
+		for f := range counts {
+			if m.inFolder(dc, f) {
+				counts[f]++
+			}
+		}
+	}
+	return counts
+}
+
+// selectFolder switches the conversation list to the given folder.
+func (m *Model) selectFolder(folder int) {
+	m.folder = folder
+	m.sidebar.Select(folder)
+	if folder == sidebar.Unread {
+		m.convList.SetFilterTab(1)
+	} else {
+		m.convList.SetFilterTab(0)
+	}
+	m.applyConversationFilter()
+	m.updateFilterCounts()
+}
+
+// toggleFilterFolder flips between Inbox and Unread, as the filter tabs do.
+func (m *Model) toggleFilterFolder() {
+	if m.folder == sidebar.Inbox {
+		m.selectFolder(sidebar.Unread)
+		return
+	}
+	m.selectFolder(sidebar.Inbox)
+}
+
+func (m Model) handleSidebarKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case m.keyIs(msg, config.ActionDown):
+		m.sidebar.MoveDown()
+		m.selectFolder(m.sidebar.Selected())
+	case m.keyIs(msg, config.ActionUp):
+		m.sidebar.MoveUp()
+		m.selectFolder(m.sidebar.Selected())
+	case m.keyIs(msg, config.ActionOpen):
+		m.setFocus(FocusConvList)
+	}
+	return m, nil
+}
+
+// toggleSelectedPinned pins or unpins the selected conversation.
+func (m Model) toggleSelectedPinned() (tea.Model, tea.Cmd) {
+	conv, ok := m.convList.SelectedConversation()
+	if !ok {
+		return m, nil
+	}
+	m.folderState.TogglePinned(conv.ID)
+	m.refreshConversationList()
+	return m, m.saveFolders()
+}
+
+// toggleSelectedMuted mutes or unmutes the selected conversation.
+func (m Model) toggleSelectedMuted() (tea.Model, tea.Cmd) {
+	conv, ok := m.convList.SelectedConversation()
+	if !ok {
+		return m, nil
+	}
+	m.folderState.ToggleMuted(conv.ID)
+	m.refreshConversationList()
+	return m, m.saveFolders()
+}
+
+// saveFolders writes a copy of the folder state to disk off the update loop.
+func (m Model) saveFolders() tea.Cmd {
+	if !m.foldersEnabled {
+		return nil
+	}
+	snap := m.folderState.Clone()
+	profile := config.ActiveProfile()
+	return m.folderSaves.save(func() error {
+		return config.InProfile(profile, func() error { return folders.Save(snap) })
+	})
+}
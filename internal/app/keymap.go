@@ -1,116 +1,157 @@
 package app
 
-import tea "github.com/charmbracelet/bubbletea"
-
-// isQuitKey returns true for quit keybindings.
-func isQuitKey(msg tea.KeyMsg) bool {
-	switch msg.String() {
-	case "q", "ctrl+c":
-		return true
-	}
-	return false
-}
-
-// isTabKey returns true for focus-cycle keys.
-func isTabKey(msg tea.KeyMsg) bool {
-	switch msg.String() {
-	case "tab", "right":
-		return true
+import (
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ggfevans/endorse/internal/config"
+	"github.com/ggfevans/endorse/internal/ui/modal"
+	"github.com/ggfevans/endorse/internal/ui/statusbar"
+)
+
+// keyIs reports whether a key is bound to an action in the active keymap.
+func (m Model) keyIs(msg tea.KeyMsg, action string) bool {
+	return m.cfg.Keys.Matches(action, msg.String())
+}
+
+// hint is a status bar entry: the first key of each action, joined with
+// "/", then a description.
+type hint struct {
+	actions []string
+	desc    string
+}
+
+// panelHints are the status bar entries for each panel.
+var panelHints = map[FocusedPanel][]hint{
+	FocusSidebar: {
+		{[]string{config.ActionDown, config.ActionUp}, "Navigate"},
+		{[]string{config.ActionNextPanel}, "Focus"},
+		{[]string{config.ActionOpen}, "Select"},
+		{[]string{config.ActionHelp}, "Help"},
+		{[]string{config.ActionQuit}, "Quit"},
+	},
+	FocusConvList: {
+		{[]string{config.ActionDown, config.ActionUp}, "Navigate"},
+		{[]string{config.ActionNextPanel}, "Focus"},
+		{[]string{config.ActionOpen}, "Select"},
+		{[]string{config.ActionFilter}, "Filter"},
+		{[]string{config.ActionSearch}, "Search"},
+		{[]string{config.ActionHelp}, "Help"},
+		{[]string{config.ActionReply}, "Reply"},
+		{[]string{config.ActionMarkRead}, "Read/Unread"},
+		{[]string{config.ActionDelete}, "Delete"},
+		{[]string{config.ActionQuit}, "Quit"},
+	},
+	FocusThread: {
+		{[]string{config.ActionDown, config.ActionUp}, "Scroll"},
+		{[]string{config.ActionPageDown, config.ActionPageUp}, "Page"},
+		{[]string{config.ActionReply}, "Reply"},
+		{[]string{config.ActionExport}, "Export"},
+		{[]string{config.ActionBack}, "Back"},
+		{[]string{config.ActionHelp}, "Help"},
+		{[]string{config.ActionQuit}, "Quit"},
+	},
+	FocusCompose: {
+		{[]string{config.ActionSend}, "Send"},
+		{[]string{config.ActionBack}, "Cancel"},
+		{[]string{config.ActionNextPanel}, "Focus"},
+	},
+}
+
+// hintsFor builds the status bar hints for a panel from the active keymap.
+// Actions with no keys are left out.
+func (m Model) hintsFor(panel FocusedPanel) []statusbar.Hint {
+	var hints []statusbar.Hint
+	for _, h := range panelHints[panel] {
+		var labels []string
+		for _, action := range h.actions {
+			if label := m.cfg.Keys.Label(action); label != "" {
+				labels = append(labels, label)
+			}
+		}
+		if len(labels) > 0 {
+			hints = append(hints, statusbar.Hint{Key: strings.Join(labels, "/"), Desc: h.desc})
+		}
 	}
-	return false
-}
-
-// isShiftTabKey returns true for reverse focus-cycle keys.
-func isShiftTabKey(msg tea.KeyMsg) bool {
-	switch msg.String() {
-	case "shift+tab", "left":
-		return true
+	return hints
+}
+
+// Help screen sections that aren't a key context of their own.
+const (
+	helpGeneral = "General"
+	helpSearch  = "Search"
+)
+
+// searchHelp lists the keys of the search field, which aren't configurable
+// since everything else types into the query.
+var searchHelp = []modal.HelpEntry{
+	{Keys: "↑/Ctrl+P", Desc: "Previous result"},
+	{Keys: "↓/Ctrl+N", Desc: "Next result"},
+	{Keys: "Enter", Desc: "Open conversation"},
+	{Keys: "Esc", Desc: "Stop searching"},
+}
+
+// helpSections lists every bound action for the help screen, grouped by
+// where its keys work. Actions that work in every panel are listed once,
+// under General.
+func (m Model) helpSections() []modal.HelpSection {
+	everywhere := func(a config.Action) bool {
+		return slices.Contains(a.Contexts, config.ContextSidebar) &&
+			slices.Contains(a.Contexts, config.ContextList) &&
+			slices.Contains(a.Contexts, config.ContextThread)
 	}
-	return false
-}
-
-// isMarkReadKey returns true for mark-as-read/unread toggle.
-func isMarkReadKey(msg tea.KeyMsg) bool {
-	return msg.String() == "m"
-}
-
-// isDeleteKey returns true for delete action.
-func isDeleteKey(msg tea.KeyMsg) bool {
-	return msg.String() == "d"
-}
-
-// isDownKey returns true for downward navigation.
-func isDownKey(msg tea.KeyMsg) bool {
-	switch msg.String() {
-	case "j", "down":
-		return true
+	entries := func(keep func(config.Action) bool) []modal.HelpEntry {
+		var entries []modal.HelpEntry
+		for _, a := range config.Actions {
+			keys := m.cfg.Keys[a.Name]
+			if len(keys) == 0 || !keep(a) {
+				continue
+			}
+			labels := make([]string, len(keys))
+			for i, key := range keys {
+				labels[i] = config.KeyLabel(key)
+			}
+			entries = append(entries, modal.HelpEntry{Keys: strings.Join(labels, "/"), Desc: a.Desc})
+		}
+		return entries
 	}
-	return false
-}
 
-// isUpKey returns true for upward navigation.
-func isUpKey(msg tea.KeyMsg) bool {
-	switch msg.String() {
-	case "k", "up":
-		return true
+	sections := []modal.HelpSection{{Title: helpGeneral, Entries: entries(everywhere)}}
+	for _, ctx := range []string{config.ContextSidebar, config.ContextList, config.ContextThread, config.ContextCompose, config.ContextConfirm} {
+		sections = append(sections, modal.HelpSection{
+			Title: sectionTitle(ctx),
+			Entries: entries(func(a config.Action) bool {
+				return slices.Contains(a.Contexts, ctx) && !everywhere(a)
+			}),
+		})
+		if ctx == config.ContextList {
+			sections = append(sections, modal.HelpSection{Title: helpSearch, Entries: searchHelp})
+		}
 	}
-	return false
-}
-
-// isEnterKey returns true for selection/confirm.
-func isEnterKey(msg tea.KeyMsg) bool {
-	return msg.String() == "enter"
-}
-
-// isEscapeKey returns true for cancel/back.
-func isEscapeKey(msg tea.KeyMsg) bool {
-	return msg.String() == "esc"
-}
-
-// isHelpKey returns true for help toggle.
-func isHelpKey(msg tea.KeyMsg) bool {
-	return msg.String() == "?"
-}
-
-// isReplyKey returns true for reply action.
-func isReplyKey(msg tea.KeyMsg) bool {
-	return msg.String() == "r"
-}
-
-// isFilterKey returns true for filter toggle.
-func isFilterKey(msg tea.KeyMsg) bool {
-	return msg.String() == "f"
-}
-
-// isSendKey returns true for message send (Ctrl+Enter in compose).
-func isSendKey(msg tea.KeyMsg) bool {
-	return msg.String() == "ctrl+s"
-}
-
-// isTopKey returns true for jump-to-top.
-func isTopKey(msg tea.KeyMsg) bool {
-	return msg.String() == "g"
-}
-
-// isBottomKey returns true for jump-to-bottom.
-func isBottomKey(msg tea.KeyMsg) bool {
-	return msg.String() == "G"
-}
-
-// isPageDown returns true for page-down scroll.
-func isPageDown(msg tea.KeyMsg) bool {
-	switch msg.String() {
-	case "ctrl+d", "pgdown":
-		return true
+	return sections
+}
+
+// helpContext returns the title of the help section for the focused panel.
+func (m Model) helpContext() string {
+	ctx := config.ContextList
+	switch m.focus {
+	case FocusSidebar:
+		ctx = config.ContextSidebar
+	case FocusConvList:
+		if m.convList.Searching() {
+			return helpSearch
+		}
+	case FocusThread:
+		ctx = config.ContextThread
+	case FocusCompose:
+		ctx = config.ContextCompose
 	}
-	return false
+	return sectionTitle(ctx)
 }
 
-// isPageUp returns true for page-up scroll.
-func isPageUp(msg tea.KeyMsg) bool {
-	switch msg.String() {
-	case "ctrl+u", "pgup":
-		return true
-	}
-	return false
+// sectionTitle capitalizes a key context for use as a heading.
+func sectionTitle(ctx string) string {
+	return strings.ToUpper(ctx[:1]) + ctx[1:]
 }
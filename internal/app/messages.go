@@ -4,6 +4,8 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ggfevans/endorse/internal/linkedin"
 )
 
 // AppState represents the top-level application state.
@@ -40,6 +42,71 @@ type TypingExpiredMsg struct {
 	Generation int
 }
 
+// OutboxSentMsg reports that a queued message was delivered.
+type OutboxSentMsg struct {
+	ItemID string
+	Sent   linkedin.MessageSentMsg
+}
+
+// OutboxSendFailedMsg reports that sending a queued message failed.
+type OutboxSendFailedMsg struct {
+	ItemID string
+	Err    error
+}
+
+// OutboxRetryMsg is sent when a queued message's backoff delay has elapsed.
+// Attempts guards against stale timers after a manual retry.
+type OutboxRetryMsg struct {
+	ItemID   string
+	Attempts int
+}
+
+// ReconnectTickMsg drives the realtime reconnect countdown.
+type ReconnectTickMsg struct {
+	Generation int
+}
+
+// ClockTickMsg is sent every minute so relative timestamps stay current.
+type ClockTickMsg struct{}
+
+// ExportDoneMsg reports that exporting a conversation finished.
+type ExportDoneMsg struct {
+	Path string
+	Err  error
+}
+
+// ClearNoticeMsg clears the status bar notice if it still reads Notice.
+type ClearNoticeMsg struct {
+	Notice string
+}
+
+// CacheFlushMsg is sent after the cache write debounce delay.
+type CacheFlushMsg struct {
+	Generation int
+}
+
+// ProfileMsg carries the result of a client call, stamped with the profile
+// generation it was made under so results for an account that has since
+// been switched away from are dropped.
+type ProfileMsg struct {
+	Generation int
+	Msg        tea.Msg
+}
+
+// clockTick returns a command that fires on the next minute boundary.
+func clockTick() tea.Cmd {
+	return tea.Every(time.Minute, func(_ time.Time) tea.Msg {
+		return ClockTickMsg{}
+	})
+}
+
+// clearNoticeAfter returns a command that clears a notice after a delay.
+func clearNoticeAfter(notice string) tea.Cmd {
+	return tea.Tick(5*time.Second, func(_ time.Time) tea.Msg {
+		return ClearNoticeMsg{Notice: notice}
+	})
+}
+
 // clearErrorAfter returns a command that clears errors after a delay.
 func clearErrorAfter() tea.Cmd {
 	return tea.Tick(5*time.Second, func(_ time.Time) tea.Msg {
@@ -0,0 +1,187 @@
+package app
+
+import (
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ggfevans/endorse/internal/config"
+	"github.com/ggfevans/endorse/internal/search"
+	"github.com/ggfevans/endorse/internal/ui/modal"
+	"github.com/ggfevans/endorse/internal/ui/sidebar"
+)
+
+const (
+	// maxRecentPicks is how many palette picks are remembered for ranking.
+	maxRecentPicks = 8
+
+	// maxPaletteItems caps how many entries the palette lists.
+	maxPaletteItems = 50
+)
+
+// Palette entry IDs are prefixed with their kind.
+const (
+	paletteAction       = "action:"
+	paletteConversation = "conversation:"
+)
+
+// paletteActions are the actions offered in the command palette, in the
+// order they're listed before anything is typed.
+var paletteActions = []string{
+	config.ActionMarkRead,
+	config.ActionDelete,
+	config.ActionExport,
+	config.ActionReply,
+	config.ActionPin,
+	config.ActionMute,
+	config.ActionFilter,
+	config.ActionSearch,
+	config.ActionTimeFormat,
+	config.ActionNextTheme,
+	config.ActionSwitchAccount,
+	config.ActionRetry,
+	config.ActionHelp,
+}
+
+// openPalette shows the command palette.
+func (m *Model) openPalette() tea.Cmd {
+	return m.paletteModal.Show(m.paletteItems(""))
+}
+
+// paletteItems matches actions and conversations against a query. Entries
+// picked recently come first, most recent first, then the rest by how well
+// they match. With no query, actions are listed before conversations.
+func (m Model) paletteItems(query string) []modal.PaletteItem {
+	type candidate struct {
+		item  modal.PaletteItem
+		score int
+	}
+	var candidates []candidate
+	add := func(item modal.PaletteItem, text string) {
+		score := 1
+		if strings.TrimSpace(query) != "" {
+			score = search.Fuzzy(query, text)
+		}
+		if score > 0 {
+			item.Recent = slices.Contains(m.recentPicks, item.ID)
+			candidates = append(candidates, candidate{item, score})
+		}
+	}
+
+	for _, name := range paletteActions {
+		i := slices.IndexFunc(config.Actions, func(a config.Action) bool { return a.Name == name })
+		desc := config.Actions[i].Desc
+		add(modal.PaletteItem{
+			ID:     paletteAction + name,
+			Title:  desc,
+			Detail: m.cfg.Keys.Label(name),
+			Kind:   "action",
+		}, desc+" "+strings.ReplaceAll(name, "_", " "))
+	}
+	for _, dc := range m.conversations {
+		var names []string
+		for _, p := range dc.Participants {
+			if !p.IsOwnUser && p.Name != "" {
+				names = append(names, p.Name)
+			}
+		}
+		detail := strings.Join(names, ", ")
+		if detail == dc.Title {
+			detail = ""
+		}
+		add(modal.PaletteItem{
+			ID:     paletteConversation + dc.ID,
+			Title:  dc.Title,
+			Detail: detail,
+			Kind:   "conversation",
+		}, dc.Title+" "+detail)
+	}
+
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		ra, rb := slices.Index(m.recentPicks, a.item.ID), slices.Index(m.recentPicks, b.item.ID)
+		switch {
+		case ra >= 0 && rb >= 0:
+			return ra - rb
+		case ra >= 0:
+			return -1
+		case rb >= 0:
+			return 1
+		}
+		return b.score - a.score
+	})
+
+	items := make([]modal.PaletteItem, 0, min(len(candidates), maxPaletteItems))
+	for _, c := range candidates[:min(len(candidates), maxPaletteItems)] {
+		items = append(items, c.item)
+	}
+	return items
+}
+
+// runPaletteItem runs a picked action or opens a picked conversation, and
+// remembers the pick.
+func (m Model) runPaletteItem(item modal.PaletteItem) (tea.Model, tea.Cmd) {
+	m.recentPicks = slices.DeleteFunc(m.recentPicks, func(id string) bool { return id == item.ID })
+	m.recentPicks = slices.Insert(m.recentPicks, 0, item.ID)
+	if len(m.recentPicks) > maxRecentPicks {
+		m.recentPicks = m.recentPicks[:maxRecentPicks]
+	}
+
+	if id, ok := strings.CutPrefix(item.ID, paletteConversation); ok {
+		return m.openConversation(id)
+	}
+	action, _ := strings.CutPrefix(item.ID, paletteAction)
+	switch action {
+	case config.ActionMarkRead:
+		return m.toggleSelectedReadState()
+	case config.ActionDelete:
+		return m.promptDeleteSelected()
+	case config.ActionExport:
+		return m, m.exportConversation()
+	case config.ActionReply:
+		return m.openSelectedConversationAndReply()
+	case config.ActionPin:
+		return m.toggleSelectedPinned()
+	case config.ActionMute:
+		return m.toggleSelectedMuted()
+	case config.ActionFilter:
+		m.toggleFilterFolder()
+	case config.ActionSearch:
+		m.setFocus(FocusConvList)
+		m.convList.StartSearch()
+		m.applyConversationFilter()
+	case config.ActionTimeFormat:
+		m.toggleTimeFormat()
+	case config.ActionNextTheme:
+		return m, m.cycleTheme()
+	case config.ActionSwitchAccount:
+		return m, m.openProfileSwitcher()
+	case config.ActionRetry:
+		return m, m.retryUnsent()
+	case config.ActionHelp:
+		m.helpModal.Show(m.helpSections(), m.helpContext())
+	}
+	return m, nil
+}
+
+// openConversation selects a conversation in the list and opens it,
+// switching to a folder that has it if the current one doesn't.
+func (m Model) openConversation(id string) (tea.Model, tea.Cmd) {
+	i := m.conversationIndex(id)
+	if i < 0 {
+		return m, nil
+	}
+	if m.convList.Searching() {
+		m.convList.StopSearch()
+		m.applyConversationFilter()
+	}
+	if dc := m.conversations[i]; !m.inFolder(dc, m.folder) {
+		folder := sidebar.Inbox
+		if dc.Archived {
+			folder = sidebar.Archived
+		}
+		m.selectFolder(folder)
+	}
+	m.convList.SelectID(id)
+	return m.openSelectedConversation()
+}
@@ -0,0 +1,43 @@
+package app
+
+import (
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// saveQueue orders the writes of one file. Snapshots are numbered as the
+// update loop takes them, and each write runs under a lock that drops any
+// snapshot older than one already written, so a slow write of stale data
+// can't land over a newer one.
+type saveQueue struct {
+	mu      sync.Mutex
+	taken   uint64 // number of the newest snapshot taken
+	written uint64 // number of the newest snapshot written
+}
+
+// save numbers a snapshot and returns a command that writes it with write,
+// unless a newer snapshot has been written by then.
+func (q *saveQueue) save(write func() error) tea.Cmd {
+	q.mu.Lock()
+	q.taken++
+	n := q.taken
+	q.mu.Unlock()
+
+	return func() tea.Msg {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		if n < q.written {
+			return nil
+		}
+		q.written = n
+		_ = write()
+		return nil
+	}
+}
+
+// saveNow writes a snapshot straight away, as when quitting, in order with
+// the writes already queued.
+func (q *saveQueue) saveNow(write func() error) {
+	q.save(write)()
+}
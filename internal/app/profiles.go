@@ -0,0 +1,207 @@
+package app
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"go.mau.fi/mautrix-linkedin/pkg/linkedingo"
+
+	"github.com/ggfevans/endorse/internal/cache"
+	"github.com/ggfevans/endorse/internal/config"
+	"github.com/ggfevans/endorse/internal/folders"
+	"github.com/ggfevans/endorse/internal/linkedin"
+	"github.com/ggfevans/endorse/internal/outbox"
+	"github.com/ggfevans/endorse/internal/ui/modal"
+)
+
+// loadProfile reads the active profile's outbox, folder state and cache, and
+// picks the starting state: auth if it has no credentials or they are
+// locked, otherwise the cached inbox (or the loading screen) while they
+// validate.
+func (m *Model) loadProfile() {
+	m.state = StateAuth
+	if m.demoMode {
+		m.state = StateLoading
+	} else {
+		m.loadCredentialState()
+	}
+
+	if m.outboxEnabled {
+		m.outbox, _ = outbox.Load()
+	}
+	if m.foldersEnabled {
+		m.folderState, _ = folders.Load()
+	}
+
+	// Show the cached inbox straight away while stored credentials validate
+	if m.cacheEnabled {
+		snap, _ := cache.Load()
+		m.cache = snap.Prune(cache.LimitsFromConfig(m.cfg.Cache), time.Now())
+		if m.state == StateLoading && len(m.cache.Conversations) > 0 {
+			m.conversations = append([]linkedin.DisplayConversation(nil), m.cache.Conversations...)
+			m.applyConversationFilter()
+			m.updateFilterCounts()
+			m.convList.Focus()
+			m.state = StateMessaging
+		}
+	}
+
+	m.header.SetProfile(profileLabel())
+}
+
+// loadCredentialState moves plaintext credentials into the configured
+// backend when that needs no passphrase, then sets up the auth form for
+// what loading them finds.
+func (m *Model) loadCredentialState() {
+	if config.NeedsMigration() && (config.CredentialBackend() != config.BackendEncrypted || config.HasPassphrase()) {
+		if err := config.MigrateCredentials(); err != nil {
+			m.authModal.SetError("Couldn't migrate credentials: " + err.Error())
+		}
+	}
+
+	creds, err := config.LoadCredentials()
+	if errors.Is(err, config.ErrWrongPassphrase) {
+		// A passphrase from earlier this session no longer fits
+		config.SetPassphrase("")
+	}
+	m.authModal.SetPassphraseMode(passphraseMode(err))
+	switch {
+	case err == nil && !creds.IsEmpty():
+		m.state = StateLoading
+	case err != nil && !errors.Is(err, config.ErrLocked) && !errors.Is(err, config.ErrWrongPassphrase):
+		m.authModal.SetError(err.Error())
+	}
+}
+
+// profileLabel is the profile name shown in the header, which is left out
+// for the default profile.
+func profileLabel() string {
+	if p := config.ActiveProfile(); p != config.DefaultProfile {
+		return p
+	}
+	return ""
+}
+
+// openProfileSwitcher shows the account switcher.
+func (m *Model) openProfileSwitcher() tea.Cmd {
+	if m.demoMode {
+		m.statusBar.SetError("Accounts can't be switched in demo mode")
+		return clearErrorAfter()
+	}
+	profiles, err := config.Profiles()
+	if err != nil {
+		m.statusBar.SetError("Couldn't list profiles: " + err.Error())
+		return clearErrorAfter()
+	}
+	// A profile chosen with --profile has no directory until it saves something
+	current := config.ActiveProfile()
+	if !slices.Contains(profiles, current) {
+		profiles = append(profiles, current)
+		slices.Sort(profiles[1:])
+	}
+	m.profilesModal.Show(profiles, current)
+	return nil
+}
+
+// switchProfile disconnects from the current account and loads another
+// profile in its place. The new profile's stored credentials are validated
+// as at startup, which rebuilds the client with the account's URN; without
+// credentials the auth prompt is shown.
+func (m Model) switchProfile(msg modal.ProfileSelectedMsg) (tea.Model, tea.Cmd) {
+	if msg.Name == config.ActiveProfile() {
+		return m, nil
+	}
+	if m.sendsInFlight() {
+		m.statusBar.SetError("Wait for messages to finish sending before switching accounts")
+		return m, clearErrorAfter()
+	}
+	if msg.New {
+		if err := config.CreateProfile(msg.Name); err != nil {
+			m.statusBar.SetError("Couldn't create profile: " + err.Error())
+			return m, clearErrorAfter()
+		}
+	}
+
+	// Write out everything the current profile holds before leaving it
+	if m.cacheEnabled && m.state == StateMessaging {
+		snap := m.cache.Prune(cache.LimitsFromConfig(m.cfg.Cache), time.Now())
+		m.cacheSaves.saveNow(func() error { return cache.Save(snap) })
+	}
+	if m.outboxEnabled {
+		m.outboxSaves.saveNow(func() error { return outbox.Save(m.outbox) })
+	}
+	if m.foldersEnabled {
+		m.folderSaves.saveNow(func() error { return folders.Save(m.folderState) })
+	}
+
+	m.setFocus(FocusConvList)
+	if m.client != nil {
+		m.client.DisconnectRealtime()
+		m.client = nil
+	}
+	m.resetReconnect()
+
+	if err := config.SetProfile(msg.Name); err != nil {
+		m.statusBar.SetError(err.Error())
+		return m, clearErrorAfter()
+	}
+
+	m.clearProfileState()
+	m.loadProfile()
+	return m, m.initAuth()
+}
+
+// clearProfileState forgets everything loaded for the previous account.
+func (m *Model) clearProfileState() {
+	m.conversations = nil
+	m.listedFromServer = false
+	m.cache = cache.Snapshot{Messages: make(map[string][]linkedin.DisplayMessage)}
+	m.outbox = outbox.Outbox{}
+	m.folderState = folders.State{}
+	m.receipts = nil
+	m.prevCursor = ""
+	m.backfillConvID = ""
+	m.pendingDeleteID = ""
+	m.pendingCreds = nil
+	m.reauth = false
+	m.typingConvID = ""
+	m.typingSentAt = time.Time{}
+	m.recentPicks = nil
+
+	// Drop any pending typing expiry, cache flush or client result meant
+	// for the old account
+	m.typingGeneration++
+	m.cacheGeneration++
+	m.profileGeneration++
+
+	m.username = ""
+	m.userURN = linkedingo.URN{}
+	m.header.SetUsername("")
+	m.statusBar.SetUsername("")
+	m.header.SetConnected(false)
+	m.statusBar.SetConnected(false)
+
+	if m.convList.Searching() {
+		m.convList.StopSearch()
+	}
+	m.convList.SetConversations(nil)
+	m.thread.Clear()
+	m.compose.Deactivate()
+	m.applyConversationFilter()
+	m.updateFilterCounts()
+
+	m.authModal = modal.NewAuth(m.styles)
+	m.authModal.SetSize(m.dims.Width, m.dims.Height)
+}
+
+// sendsInFlight reports whether any outbox message is waiting on a send.
+func (m Model) sendsInFlight() bool {
+	for _, item := range m.outbox.Items {
+		if item.Sending {
+			return true
+		}
+	}
+	return false
+}
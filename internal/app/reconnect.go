@@ -0,0 +1,102 @@
+package app
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Realtime reconnect backoff bounds.
+const (
+	reconnectBaseDelay = time.Second
+	reconnectMaxDelay  = 2 * time.Minute
+)
+
+// reconnectState tracks the realtime reconnect supervisor. The supervisor
+// owns retrying: when the stream drops it stops the client's own loop,
+// waits out a jittered exponential delay, and connects again.
+type reconnectState struct {
+	attempt    int       // consecutive failed connections (0 = connected)
+	at         time.Time // when the next attempt fires; zero once started
+	generation int       // guards countdown ticks against stale timers
+}
+
+// pending reports whether a reconnect is scheduled or in progress.
+func (r reconnectState) pending() bool {
+	return r.attempt > 0
+}
+
+// reconnectDelay returns the delay before the given reconnect attempt
+// (1-based). The ceiling doubles from one second up to two minutes, and the
+// delay is drawn uniformly from its upper half so clients that dropped
+// together don't reconnect in lockstep. jitter must return a value in [0, 1).
+func reconnectDelay(attempt int, jitter func() float64) time.Duration {
+	ceiling := reconnectBaseDelay
+	for i := 1; i < attempt && ceiling < reconnectMaxDelay; i++ {
+		ceiling *= 2
+	}
+	ceiling = min(ceiling, reconnectMaxDelay)
+	return ceiling/2 + time.Duration(jitter()*float64(ceiling/2))
+}
+
+// scheduleReconnect starts the countdown to the next reconnect attempt.
+// Disconnects reported while a reconnect is already pending are ignored.
+func (m *Model) scheduleReconnect() tea.Cmd {
+	if m.client == nil || (m.reconnect.pending() && !m.reconnect.at.IsZero()) {
+		return nil
+	}
+	m.reconnect.attempt++
+	delay := reconnectDelay(m.reconnect.attempt, rand.Float64)
+	m.reconnect.at = time.Now().Add(delay)
+	m.reconnect.generation++
+	m.statusBar.SetNotice(reconnectNotice(delay))
+	return reconnectTick(m.reconnect.generation, delay)
+}
+
+// handleReconnectTick updates the countdown and reconnects once it expires.
+func (m *Model) handleReconnectTick(msg ReconnectTickMsg) tea.Cmd {
+	if msg.Generation != m.reconnect.generation || m.reconnect.at.IsZero() {
+		return nil
+	}
+	if m.client == nil {
+		m.resetReconnect()
+		return nil
+	}
+
+	remaining := time.Until(m.reconnect.at)
+	if remaining > 0 {
+		m.statusBar.SetNotice(reconnectNotice(remaining))
+		return reconnectTick(msg.Generation, remaining)
+	}
+
+	m.reconnect.at = time.Time{}
+	m.statusBar.SetNotice("Reconnecting…")
+	client := m.client
+	return m.forProfile(func() tea.Msg {
+		// Stop the client's own retry loop before starting a fresh one
+		client.DisconnectRealtime()
+		return client.ConnectRealtime()()
+	})
+}
+
+// resetReconnect clears the supervisor state and cancels pending ticks.
+func (m *Model) resetReconnect() {
+	m.reconnect.attempt = 0
+	m.reconnect.at = time.Time{}
+	m.reconnect.generation++
+	m.statusBar.SetNotice("")
+}
+
+// reconnectTick fires after a second, or sooner if the deadline is closer.
+func reconnectTick(gen int, remaining time.Duration) tea.Cmd {
+	return tea.Tick(min(remaining, time.Second), func(_ time.Time) tea.Msg {
+		return ReconnectTickMsg{Generation: gen}
+	})
+}
+
+func reconnectNotice(remaining time.Duration) string {
+	secs := int((remaining + time.Second - 1) / time.Second)
+	return fmt.Sprintf("Disconnected · reconnecting in %ds", max(secs, 1))
+}
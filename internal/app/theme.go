@@ -0,0 +1,60 @@
+package app
+
+import (
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ggfevans/endorse/internal/config"
+	"github.com/ggfevans/endorse/internal/ui/styles"
+)
+
+// applyTheme rebuilds the styles from a theme and hands them to every
+// component.
+func (m *Model) applyTheme(theme config.Theme) {
+	m.theme = theme
+	m.styles = styles.NewFor(theme, m.terminal)
+
+	m.header.SetStyles(m.styles)
+	m.statusBar.SetStyles(m.styles)
+	m.sidebar.SetStyles(m.styles)
+	m.convList.SetStyles(m.styles)
+	m.thread.SetStyles(m.styles)
+	m.compose.SetStyles(m.styles)
+	m.authModal.SetStyles(m.styles)
+	m.confirmModal.SetStyles(m.styles)
+	m.profilesModal.SetStyles(m.styles)
+	m.helpModal.SetStyles(m.styles)
+	m.paletteModal.SetStyles(m.styles)
+	m.thread.SetComposeView(m.compose.View())
+}
+
+// cycleTheme switches to the next built-in or user theme, skipping theme
+// files that don't load. The choice lasts for this session; set theme in
+// config.toml to keep it.
+func (m *Model) cycleTheme() tea.Cmd {
+	names, err := config.Themes()
+	if err != nil {
+		m.statusBar.SetError("Couldn't list themes: " + err.Error())
+		return clearErrorAfter()
+	}
+
+	var cmds []tea.Cmd
+	current := slices.Index(names, m.theme.Name)
+	for i := 1; i <= len(names); i++ {
+		theme, err := config.LoadTheme(names[(current+i)%len(names)])
+		if err != nil {
+			m.statusBar.SetError(err.Error())
+			cmds = append(cmds, clearErrorAfter())
+			continue
+		}
+		m.applyTheme(theme)
+		if !m.reconnect.pending() { // the reconnect countdown keeps the notice
+			notice := "Theme: " + theme.Name
+			m.statusBar.SetNotice(notice)
+			cmds = append(cmds, clearNoticeAfter(notice))
+		}
+		break
+	}
+	return tea.Batch(cmds...)
+}
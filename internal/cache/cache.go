@@ -0,0 +1,196 @@
+package cache
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ggfevans/endorse/internal/config"
+	"github.com/ggfevans/endorse/internal/linkedin"
+)
+
+// version is bumped whenever the on-disk format changes incompatibly.
+// Snapshots written with a different version are discarded on load.
+const version = 1
+
+// Snapshot is the on-disk message cache. Conversations and messages are
+// keyed by URN: conversations by their own URN, messages by the URN of the
+// conversation they belong to.
+type Snapshot struct {
+	Version       int                                  `json:"version"`
+	SavedAt       time.Time                            `json:"saved_at"`
+	Conversations []linkedin.DisplayConversation       `json:"conversations"`
+	Messages      map[string][]linkedin.DisplayMessage `json:"messages"`
+}
+
+// Limits bounds how much the cache keeps. Zero values mean unlimited.
+type Limits struct {
+	MaxAge      time.Duration
+	MaxMessages int // per conversation
+}
+
+// LimitsFromConfig converts the cache section of the config into Limits.
+func LimitsFromConfig(c config.CacheConfig) Limits {
+	return Limits{
+		MaxAge:      time.Duration(c.MaxAgeDays) * 24 * time.Hour,
+		MaxMessages: c.MaxMessages,
+	}
+}
+
+// Path returns the path to the active profile's cache file.
+func Path() (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cache.json"), nil
+}
+
+// Load reads the cache from disk, returning an empty snapshot if the file
+// doesn't exist or was written by an incompatible version.
+func Load() (Snapshot, error) {
+	snap := Snapshot{Messages: make(map[string][]linkedin.DisplayMessage)}
+
+	path, err := Path()
+	if err != nil {
+		return snap, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return snap, nil
+		}
+		return snap, err
+	}
+
+	var loaded Snapshot
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		return snap, err
+	}
+	if loaded.Version != version {
+		return snap, nil
+	}
+	if loaded.Messages == nil {
+		loaded.Messages = make(map[string][]linkedin.DisplayMessage)
+	}
+
+	return loaded, nil
+}
+
+// Save writes the snapshot to disk with restricted permissions. The file is
+// replaced atomically so a crash mid-write never leaves a truncated cache.
+func Save(snap Snapshot) error {
+	dir, err := config.DataDir()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	snap.Version = version
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+
+	return config.WriteFile(filepath.Join(dir, "cache.json"), data)
+}
+
+// Clear removes the cache file.
+func Clear() error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// SetConversations replaces the cached conversation list.
+func (s *Snapshot) SetConversations(convs []linkedin.DisplayConversation) {
+	s.Conversations = append([]linkedin.DisplayConversation(nil), convs...)
+}
+
+// RemoveConversation drops a conversation and its messages.
+func (s *Snapshot) RemoveConversation(id string) {
+	for i, dc := range s.Conversations {
+		if dc.ID == id {
+			s.Conversations = append(s.Conversations[:i:i], s.Conversations[i+1:]...)
+			break
+		}
+	}
+	delete(s.Messages, id)
+}
+
+// ConversationMessages returns the cached messages for a conversation.
+func (s Snapshot) ConversationMessages(id string) []linkedin.DisplayMessage {
+	return s.Messages[id]
+}
+
+// MergeMessages adds messages to a conversation, deduplicating by message ID
+// and keeping them in chronological order.
+func (s *Snapshot) MergeMessages(id string, msgs []linkedin.DisplayMessage) {
+	if s.Messages == nil {
+		s.Messages = make(map[string][]linkedin.DisplayMessage)
+	}
+
+	existing := s.Messages[id]
+	index := make(map[string]int, len(existing))
+	merged := append([]linkedin.DisplayMessage(nil), existing...)
+	for i, dm := range merged {
+		index[dm.ID] = i
+	}
+	for _, dm := range msgs {
+		if i, ok := index[dm.ID]; ok {
+			merged[i] = dm
+			continue
+		}
+		index[dm.ID] = len(merged)
+		merged = append(merged, dm)
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Timestamp.Before(merged[j].Timestamp)
+	})
+	s.Messages[id] = merged
+}
+
+// Prune returns a copy of the snapshot with conversations older than the
+// age limit dropped and each conversation trimmed to its newest messages.
+func (s Snapshot) Prune(l Limits, now time.Time) Snapshot {
+	out := Snapshot{
+		Version:  s.Version,
+		SavedAt:  now,
+		Messages: make(map[string][]linkedin.DisplayMessage, len(s.Messages)),
+	}
+
+	keep := make(map[string]bool, len(s.Conversations))
+	for _, dc := range s.Conversations {
+		if l.MaxAge > 0 && now.Sub(dc.LastActivityAt) > l.MaxAge {
+			continue
+		}
+		out.Conversations = append(out.Conversations, dc)
+		keep[dc.ID] = true
+	}
+
+	for id, msgs := range s.Messages {
+		if !keep[id] {
+			continue
+		}
+		if l.MaxMessages > 0 && len(msgs) > l.MaxMessages {
+			msgs = msgs[len(msgs)-l.MaxMessages:]
+		}
+		out.Messages[id] = append([]linkedin.DisplayMessage(nil), msgs...)
+	}
+
+	return out
+}
@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"go.mau.fi/mautrix-linkedin/pkg/linkedingo"
+
+	"github.com/ggfevans/endorse/internal/linkedin"
+)
+
+func sampleConversation(id string, lastActivity time.Time) linkedin.DisplayConversation {
+	urn := linkedingo.NewURN("urn:li:msg_conversation:" + id)
+	return linkedin.DisplayConversation{
+		ID:             urn.String(),
+		Title:          "Conversation " + id,
+		LastActivityAt: lastActivity,
+		URN:            urn,
+	}
+}
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	now := time.Now().Truncate(time.Second)
+	conv := sampleConversation("a", now)
+
+	var snap Snapshot
+	snap.SetConversations([]linkedin.DisplayConversation{conv})
+	snap.MergeMessages(conv.ID, []linkedin.DisplayMessage{
+		{ID: "m1", Sender: "Alice", Body: "Hello", Timestamp: now},
+	})
+
+	if err := Save(snap); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(loaded.Conversations) != 1 {
+		t.Fatalf("expected 1 conversation, got %d", len(loaded.Conversations))
+	}
+	if loaded.Conversations[0].URN != conv.URN {
+		t.Errorf("expected URN %q to round-trip, got %q", conv.URN, loaded.Conversations[0].URN)
+	}
+	msgs := loaded.ConversationMessages(conv.ID)
+	if len(msgs) != 1 || msgs[0].Body != "Hello" {
+		t.Errorf("expected cached message to round-trip, got %+v", msgs)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	snap, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(snap.Conversations) != 0 {
+		t.Errorf("expected empty snapshot, got %d conversations", len(snap.Conversations))
+	}
+}
+
+func TestMergeMessagesDeduplicates(t *testing.T) {
+	now := time.Now()
+	var snap Snapshot
+	snap.MergeMessages("c", []linkedin.DisplayMessage{
+		{ID: "m2", Body: "second", Timestamp: now},
+	})
+	snap.MergeMessages("c", []linkedin.DisplayMessage{
+		{ID: "m1", Body: "first", Timestamp: now.Add(-time.Minute)},
+		{ID: "m2", Body: "second (edited)", Timestamp: now},
+	})
+
+	msgs := snap.ConversationMessages("c")
+	if len(msgs) != 2 {
+		t.Fatalf("expected 2 messages after merge, got %d", len(msgs))
+	}
+	if msgs[0].ID != "m1" {
+		t.Errorf("expected messages in chronological order, got %q first", msgs[0].ID)
+	}
+	if msgs[1].Body != "second (edited)" {
+		t.Errorf("expected duplicate ID to be replaced, got %q", msgs[1].Body)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	now := time.Now()
+	fresh := sampleConversation("fresh", now.Add(-time.Hour))
+	stale := sampleConversation("stale", now.Add(-48*time.Hour))
+
+	var snap Snapshot
+	snap.SetConversations([]linkedin.DisplayConversation{fresh, stale})
+	var msgs []linkedin.DisplayMessage
+	for i := 0; i < 5; i++ {
+		msgs = append(msgs, linkedin.DisplayMessage{
+			ID:        fmt.Sprintf("m%d", i),
+			Timestamp: now.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	snap.MergeMessages(fresh.ID, msgs)
+	snap.MergeMessages(stale.ID, msgs)
+
+	pruned := snap.Prune(Limits{MaxAge: 24 * time.Hour, MaxMessages: 3}, now)
+
+	if len(pruned.Conversations) != 1 || pruned.Conversations[0].ID != fresh.ID {
+		t.Fatalf("expected only the fresh conversation to survive, got %+v", pruned.Conversations)
+	}
+	if _, ok := pruned.Messages[stale.ID]; ok {
+		t.Error("expected messages of a pruned conversation to be dropped")
+	}
+	kept := pruned.ConversationMessages(fresh.ID)
+	if len(kept) != 3 || kept[0].ID != "m2" {
+		t.Errorf("expected newest 3 messages starting at m2, got %+v", kept)
+	}
+}
+
+func TestRemoveConversation(t *testing.T) {
+	a := sampleConversation("a", time.Now())
+	b := sampleConversation("b", time.Now())
+
+	var snap Snapshot
+	snap.SetConversations([]linkedin.DisplayConversation{a, b})
+	snap.MergeMessages(a.ID, []linkedin.DisplayMessage{{ID: "m1"}})
+	snap.RemoveConversation(a.ID)
+
+	if len(snap.Conversations) != 1 || snap.Conversations[0].ID != b.ID {
+		t.Errorf("expected only conversation b to remain, got %+v", snap.Conversations)
+	}
+	if len(snap.ConversationMessages(a.ID)) != 0 {
+		t.Error("expected messages of removed conversation to be dropped")
+	}
+}
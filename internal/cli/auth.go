@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ggfevans/endorse/internal/config"
+	"github.com/ggfevans/endorse/internal/cookieimport"
+)
+
+// runAuth handles "auth import", which signs in from a cookies.txt file or
+// HAR capture instead of a pasted cookie. The session is validated against
+// LinkedIn before anything is saved, so there is no --demo mode for it.
+func runAuth(e *env, args []string) error {
+	if len(args) != 2 || args[0] != "import" {
+		return errUsage
+	}
+	if e.opts.demo {
+		return errors.New("auth import can't be used with --demo: the session has to be checked with LinkedIn before it's saved")
+	}
+
+	var data []byte
+	var err error
+	if args[1] == "-" {
+		data, err = io.ReadAll(e.stdin)
+	} else {
+		data, err = os.ReadFile(args[1])
+	}
+	if err != nil {
+		return err
+	}
+	session, err := cookieimport.Parse(data)
+	if err != nil {
+		return err
+	}
+	creds := config.Credentials{
+		Cookie:       session.Cookie,
+		PageInstance: session.PageInstance,
+		XLiTrack:     session.XLiTrack,
+	}
+
+	// Check the credentials can be saved before signing in with them
+	if err := useCredentialBackend(); err != nil {
+		return err
+	}
+	if config.CredentialBackend() == config.BackendEncrypted && !config.HasPassphrase() {
+		return fmt.Errorf("credentials are stored encrypted; set %s to the passphrase to save them with", passphraseEnv)
+	}
+
+	_, name, err := signIn(creds)
+	if err != nil {
+		return err
+	}
+
+	// Don't leave an old plaintext file behind to be migrated over these
+	if config.NeedsMigration() {
+		if err := config.MigrateCredentials(); err != nil {
+			return fmt.Errorf("migrating credentials: %w", err)
+		}
+	}
+	if err := config.SaveCredentials(creds); err != nil {
+		if errors.Is(err, config.ErrReadOnly) {
+			return fmt.Errorf("signed in as %s, but %w", name, err)
+		}
+		return fmt.Errorf("saving credentials: %w", err)
+	}
+
+	fmt.Fprintf(e.stdout, "Signed in as %s (profile %s)\n", name, config.ActiveProfile())
+	return nil
+}
@@ -0,0 +1,572 @@
+// Package cli implements endorse's headless subcommands, which drive the
+// messaging client directly for use in shell scripts and cron jobs.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.mau.fi/mautrix-linkedin/pkg/linkedingo"
+
+	"github.com/ggfevans/endorse/internal/config"
+	"github.com/ggfevans/endorse/internal/export"
+	"github.com/ggfevans/endorse/internal/linkedin"
+	"github.com/ggfevans/endorse/internal/util"
+)
+
+// defaultMessageLimit is how many messages `endorse messages` prints.
+const defaultMessageLimit = 20
+
+// errUsage marks errors caused by bad arguments rather than a failed call.
+var errUsage = errors.New("usage")
+
+// command is a headless subcommand. Offline commands run without signing
+// in first, so env.client is nil for them.
+type command struct {
+	usage   string
+	run     func(e *env, args []string) error
+	offline bool
+}
+
+var commands = map[string]command{
+	"conversations": {"conversations", runConversations, false},
+	"messages":      {"messages <conversation>", runMessages, false},
+	"send":          {"send <conversation> [text|-]", runSend, false},
+	"mark-read":     {"mark-read <conversation>", runMarkRead, false},
+	"export":        {"export [conversation...] [--format=markdown|json|mbox] [--since=DATE] [--until=DATE] [--participant=NAME] [--output=FILE]", runExport, false},
+	"auth":          {"auth import <cookies.txt|file.har|->", runAuth, true},
+}
+
+// env is what a subcommand runs against.
+type env struct {
+	client *linkedin.Sync
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	opts   options
+}
+
+// options are the flags accepted by the subcommands.
+type options struct {
+	json    bool
+	demo    bool
+	limit   int
+	profile string // "" keeps the default profile
+
+	// export
+	format      export.Format
+	since       time.Time
+	until       time.Time // exclusive: the day after --until
+	participant string
+	output      string
+}
+
+// IsCommand reports whether name is a headless subcommand.
+func IsCommand(name string) bool {
+	_, ok := commands[name]
+	return ok
+}
+
+// Run executes the subcommand named by args[0] and returns the process exit
+// code: 0 on success, 1 if the command failed and 2 for bad arguments.
+func Run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "endorse: unknown command %q\n", args[0])
+		printUsage(stderr)
+		return 2
+	}
+
+	opts, positional, err := parseArgs(args[1:])
+	if err != nil {
+		fmt.Fprintf(stderr, "endorse: %v\n", err)
+		fmt.Fprintf(stderr, "usage: endorse %s\n", cmd.usage)
+		return 2
+	}
+
+	if opts.profile != "" {
+		// Already validated, so this can't fail
+		_ = config.SetProfile(opts.profile)
+	}
+
+	e := &env{stdin: stdin, stdout: stdout, stderr: stderr, opts: opts}
+	if !cmd.offline {
+		if e.client, err = connect(opts.demo); err != nil {
+			fmt.Fprintf(stderr, "endorse: %v\n", err)
+			return 1
+		}
+	}
+	if err := cmd.run(e, positional); err != nil {
+		if errors.Is(err, errUsage) {
+			fmt.Fprintf(stderr, "usage: endorse %s\n", cmd.usage)
+			return 2
+		}
+		fmt.Fprintf(stderr, "endorse: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// printUsage lists the subcommands and shared flags.
+func printUsage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "commands:")
+	for _, name := range names {
+		fmt.Fprintf(w, "  endorse %s\n", commands[name].usage)
+	}
+	fmt.Fprintln(w, "flags: --json, --demo, --profile=NAME, --limit=N (messages); dates are YYYY-MM-DD")
+}
+
+// parseArgs separates the shared flags from positional arguments. Flags may
+// appear anywhere; "--" ends flag parsing and a lone "-" is positional.
+func parseArgs(args []string) (options, []string, error) {
+	opts := options{limit: defaultMessageLimit}
+	var positional []string
+	for i, arg := range args {
+		switch {
+		case arg == "--":
+			return opts, append(positional, args[i+1:]...), nil
+		case arg == "--json":
+			opts.json = true
+		case arg == "--demo":
+			opts.demo = true
+		case strings.HasPrefix(arg, "--profile="):
+			opts.profile = strings.TrimPrefix(arg, "--profile=")
+			if err := config.ValidateProfileName(opts.profile); err != nil {
+				return opts, nil, err
+			}
+		case strings.HasPrefix(arg, "--limit="):
+			n, err := strconv.Atoi(strings.TrimPrefix(arg, "--limit="))
+			if err != nil || n < 1 {
+				return opts, nil, fmt.Errorf("invalid --limit %q", strings.TrimPrefix(arg, "--limit="))
+			}
+			opts.limit = n
+		case strings.HasPrefix(arg, "--format="):
+			f, err := export.ParseFormat(strings.TrimPrefix(arg, "--format="))
+			if err != nil {
+				return opts, nil, err
+			}
+			opts.format = f
+		case strings.HasPrefix(arg, "--since="):
+			t, err := parseDate(strings.TrimPrefix(arg, "--since="))
+			if err != nil {
+				return opts, nil, fmt.Errorf("invalid --since: %w", err)
+			}
+			opts.since = t
+		case strings.HasPrefix(arg, "--until="):
+			t, err := parseDate(strings.TrimPrefix(arg, "--until="))
+			if err != nil {
+				return opts, nil, fmt.Errorf("invalid --until: %w", err)
+			}
+			opts.until = t.AddDate(0, 0, 1)
+		case strings.HasPrefix(arg, "--participant="):
+			opts.participant = strings.TrimPrefix(arg, "--participant=")
+		case strings.HasPrefix(arg, "--output="):
+			opts.output = strings.TrimPrefix(arg, "--output=")
+		case strings.HasPrefix(arg, "-") && arg != "-":
+			return opts, nil, fmt.Errorf("unknown flag %s", arg)
+		default:
+			positional = append(positional, arg)
+		}
+	}
+	return opts, positional, nil
+}
+
+// parseDate parses a YYYY-MM-DD date as local midnight.
+func parseDate(s string) (time.Time, error) {
+	return time.ParseInLocation("2006-01-02", s, time.Local)
+}
+
+// passphraseEnv holds the passphrase for encrypted credentials, since the
+// subcommands never prompt.
+const passphraseEnv = "ENDORSE_PASSPHRASE"
+
+// connect builds a client from the stored credentials, or the demo client,
+// and validates it.
+func connect(demo bool) (*linkedin.Sync, error) {
+	if demo {
+		return linkedin.NewSync(linkedin.NewDemoClient()), nil
+	}
+
+	creds, err := loadCredentials()
+	if err != nil {
+		return nil, err
+	}
+	if creds.IsEmpty() {
+		if p := config.ActiveProfile(); p != config.DefaultProfile {
+			return nil, fmt.Errorf("profile %s is not signed in; run endorse --profile=%s once to sign in", p, p)
+		}
+		return nil, errors.New("not signed in; run endorse once to sign in")
+	}
+
+	s, _, err := signIn(creds)
+	return s, err
+}
+
+// newClient builds a LinkedIn client from credentials, for the user's URN
+// once it's known. Tests replace it.
+var newClient = func(ctx context.Context, creds config.Credentials, urn linkedingo.URN) (linkedin.MessagingClient, error) {
+	var client *linkedin.Client
+	var err error
+	if urn.IsEmpty() {
+		client, err = linkedin.New(ctx, creds.Cookie, creds.PageInstance, creds.XLiTrack)
+	} else {
+		client, err = linkedin.NewWithURN(ctx, creds.Cookie, creds.PageInstance, creds.XLiTrack, urn)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// signIn validates credentials, returning a client for the signed-in user
+// and their name. The client used to validate has no mailbox to query, so
+// it's rebuilt with the user's URN, as the TUI does.
+func signIn(creds config.Credentials) (*linkedin.Sync, string, error) {
+	noop := zerolog.Nop()
+	ctx := noop.WithContext(context.Background())
+	client, err := newClient(ctx, creds, linkedingo.URN{})
+	if err != nil {
+		return nil, "", err
+	}
+
+	name, urn, err := linkedin.NewSync(client).ValidateAuth()
+	if err != nil {
+		return nil, "", fmt.Errorf("authentication failed: %w", err)
+	}
+	if urn.IsEmpty() {
+		return nil, "", errors.New("authentication failed: LinkedIn didn't return the user's URN")
+	}
+
+	client, err = newClient(ctx, creds, urn)
+	if err != nil {
+		return nil, "", err
+	}
+	return linkedin.NewSync(client), name, nil
+}
+
+// useCredentialBackend selects the configured credential backend, with the
+// passphrase from the environment if there is one.
+func useCredentialBackend() error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if err := config.UseCredentialBackend(cfg.Credentials); err != nil {
+		return err
+	}
+	if p := os.Getenv(passphraseEnv); p != "" {
+		config.SetPassphrase(p)
+	}
+	return nil
+}
+
+// loadCredentials reads credentials through the configured backend,
+// migrating a plaintext file into it first when it can.
+func loadCredentials() (config.Credentials, error) {
+	var creds config.Credentials
+	if err := useCredentialBackend(); err != nil {
+		return creds, err
+	}
+
+	if config.NeedsMigration() {
+		if config.CredentialBackend() == config.BackendEncrypted && !config.HasPassphrase() {
+			return creds, fmt.Errorf("saved credentials need encrypting; set %s or run endorse to choose a passphrase", passphraseEnv)
+		}
+		if err := config.MigrateCredentials(); err != nil {
+			return creds, fmt.Errorf("migrating credentials: %w", err)
+		}
+	}
+
+	creds, err := config.LoadCredentials()
+	switch {
+	case errors.Is(err, config.ErrLocked):
+		return creds, fmt.Errorf("saved credentials are encrypted; set %s to unlock them", passphraseEnv)
+	case err != nil:
+		return creds, fmt.Errorf("loading credentials: %w", err)
+	}
+	return creds, nil
+}
+
+// conversationURNPrefix is what LinkedIn puts before a conversation's ID,
+// used to turn a bare ID back into its URN.
+const conversationURNPrefix = "urn:li:msg_conversation:"
+
+// resolveConversation finds a conversation by ID or by name. An ID or URN is
+// used as given, so it reaches conversations too old for the first page.
+// Names page back through the inbox until something matches, matching
+// exactly first, then as a unique case-insensitive substring.
+func (e *env) resolveConversation(query string) (linkedin.DisplayConversation, error) {
+	convs, err := e.client.Conversations()
+	if err != nil {
+		return linkedin.DisplayConversation{}, err
+	}
+
+	if urn, ok := conversationURN(query); ok {
+		// Prefer the listed copy for its title and participants
+		for _, c := range convs {
+			if c.ID == urn.String() {
+				return c, nil
+			}
+		}
+		return linkedin.DisplayConversation{ID: urn.String(), URN: urn, Title: query}, nil
+	}
+
+	seen := make(map[string]bool, len(convs))
+	for page := convs; len(page) > 0; {
+		var matches []linkedin.DisplayConversation
+		q := strings.ToLower(query)
+		for _, c := range page {
+			seen[c.ID] = true
+			if c.ID == query || c.URN.ID() == query || strings.EqualFold(c.Title, query) {
+				return c, nil
+			}
+			if strings.Contains(strings.ToLower(c.Title), q) {
+				matches = append(matches, c)
+			}
+		}
+		switch len(matches) {
+		case 0:
+		case 1:
+			return matches[0], nil
+		default:
+			var names []string
+			for _, c := range matches {
+				names = append(names, c.Title)
+			}
+			return linkedin.DisplayConversation{}, fmt.Errorf("%q matches several conversations: %s", query, strings.Join(names, ", "))
+		}
+
+		older, err := e.client.ConversationsBefore(page[len(page)-1].LastActivityAt)
+		if err != nil {
+			return linkedin.DisplayConversation{}, err
+		}
+		page = nil
+		for _, c := range older {
+			if !seen[c.ID] {
+				page = append(page, c)
+			}
+		}
+	}
+	return linkedin.DisplayConversation{}, fmt.Errorf("no conversation matches %q", query)
+}
+
+// conversationURN reports whether query is a conversation URN or the
+// parenthesised ID inside one, and returns the URN it names.
+func conversationURN(query string) (linkedingo.URN, bool) {
+	switch {
+	case strings.HasPrefix(query, "urn:li:"):
+		return linkedingo.NewURN(query), true
+	case strings.HasPrefix(query, "(") && strings.HasSuffix(query, ")"):
+		return linkedingo.NewURN(conversationURNPrefix + query), true
+	}
+	return linkedingo.URN{}, false
+}
+
+// --- Subcommands ---
+
+func runConversations(e *env, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+	convs, err := e.client.Conversations()
+	if err != nil {
+		return err
+	}
+
+	if e.opts.json {
+		out := make([]conversationJSON, 0, len(convs))
+		for _, c := range convs {
+			out = append(out, toConversationJSON(c))
+		}
+		return e.writeJSON(out)
+	}
+
+	tw := tabwriter.NewWriter(e.stdout, 0, 0, 2, ' ', 0)
+	for _, c := range convs {
+		unread := " "
+		if c.Unread {
+			unread = "●"
+		}
+		count := ""
+		if n := c.UnreadMessages(); n > 0 {
+			count = strconv.Itoa(n)
+		}
+		fmt.Fprintf(tw, "%s %s\t%s\t%s\t%s\n", unread, count, util.RelativeTime(c.LastActivityAt),
+			c.Title, util.Truncate(oneLine(c.LastMessage), 60))
+	}
+	return tw.Flush()
+}
+
+func runMessages(e *env, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	conv, err := e.resolveConversation(args[0])
+	if err != nil {
+		return err
+	}
+	msgs, _, err := e.client.Messages(conv.URN, e.opts.limit)
+	if err != nil {
+		return err
+	}
+
+	// Oldest first, keeping the newest if the page came back larger
+	sort.SliceStable(msgs, func(i, j int) bool { return msgs[i].Timestamp.Before(msgs[j].Timestamp) })
+	if len(msgs) > e.opts.limit {
+		msgs = msgs[len(msgs)-e.opts.limit:]
+	}
+
+	if e.opts.json {
+		out := make([]messageJSON, 0, len(msgs))
+		for _, m := range msgs {
+			out = append(out, toMessageJSON(conv.ID, m))
+		}
+		return e.writeJSON(out)
+	}
+
+	for _, m := range msgs {
+		fmt.Fprintf(e.stdout, "[%s] %s: %s\n", m.Timestamp.Format("2006-01-02 15:04"), m.Sender, m.Body)
+	}
+	return nil
+}
+
+func runSend(e *env, args []string) error {
+	if len(args) < 1 {
+		return errUsage
+	}
+	conv, err := e.resolveConversation(args[0])
+	if err != nil {
+		return err
+	}
+
+	// The text comes from the arguments, or stdin if it is "-" or missing
+	var text string
+	if len(args) == 1 || (len(args) == 2 && args[1] == "-") {
+		data, err := io.ReadAll(e.stdin)
+		if err != nil {
+			return fmt.Errorf("reading message from stdin: %w", err)
+		}
+		text = string(data)
+	} else {
+		text = strings.Join(args[1:], " ")
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return errors.New("message is empty")
+	}
+
+	sent, err := e.client.Send(conv.URN, text)
+	if err != nil {
+		return err
+	}
+
+	if e.opts.json {
+		return e.writeJSON(toMessageJSON(conv.ID, sent))
+	}
+	fmt.Fprintf(e.stdout, "Sent to %s\n", conv.Title)
+	return nil
+}
+
+func runMarkRead(e *env, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	conv, err := e.resolveConversation(args[0])
+	if err != nil {
+		return err
+	}
+	if err := e.client.MarkRead(conv.URN); err != nil {
+		return err
+	}
+
+	if e.opts.json {
+		return e.writeJSON(struct {
+			ID   string `json:"id"`
+			Read bool   `json:"read"`
+		}{conv.ID, true})
+	}
+	fmt.Fprintf(e.stdout, "Marked %s as read\n", conv.Title)
+	return nil
+}
+
+// --- JSON output ---
+
+type conversationJSON struct {
+	ID             string    `json:"id"`
+	Title          string    `json:"title"`
+	Participants   []string  `json:"participants"`
+	LastMessage    string    `json:"last_message"`
+	LastActivityAt time.Time `json:"last_activity_at"`
+	Unread         bool      `json:"unread"`
+	UnreadCount    int       `json:"unread_count"`
+}
+
+type messageJSON struct {
+	ID             string    `json:"id"`
+	ConversationID string    `json:"conversation_id"`
+	Sender         string    `json:"sender"`
+	SenderURN      string    `json:"sender_urn,omitempty"`
+	Body           string    `json:"body"`
+	Timestamp      time.Time `json:"timestamp"`
+	IsOwn          bool      `json:"is_own"`
+}
+
+func toConversationJSON(c linkedin.DisplayConversation) conversationJSON {
+	out := conversationJSON{
+		ID:             c.ID,
+		Title:          c.Title,
+		Participants:   []string{},
+		LastMessage:    c.LastMessage,
+		LastActivityAt: c.LastActivityAt,
+		Unread:         c.Unread,
+		UnreadCount:    c.UnreadMessages(),
+	}
+	for _, p := range c.Participants {
+		if !p.IsOwnUser {
+			out.Participants = append(out.Participants, p.Name)
+		}
+	}
+	return out
+}
+
+func toMessageJSON(convID string, m linkedin.DisplayMessage) messageJSON {
+	out := messageJSON{
+		ID:             m.ID,
+		ConversationID: convID,
+		Sender:         m.Sender,
+		Body:           m.Body,
+		Timestamp:      m.Timestamp,
+		IsOwn:          m.IsOwn,
+	}
+	if m.SenderURN != (linkedingo.URN{}) {
+		out.SenderURN = m.SenderURN.String()
+	}
+	return out
+}
+
+func (e *env) writeJSON(v any) error {
+	enc := json.NewEncoder(e.stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// oneLine collapses a message preview onto a single line.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
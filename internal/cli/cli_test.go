@@ -0,0 +1,297 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"go.mau.fi/mautrix-linkedin/pkg/linkedingo"
+
+	"github.com/ggfevans/endorse/internal/config"
+	"github.com/ggfevans/endorse/internal/linkedin"
+)
+
+func run(t *testing.T, stdin string, args ...string) (string, string, int) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := Run(append(args, "--demo"), strings.NewReader(stdin), &stdout, &stderr)
+	return stdout.String(), stderr.String(), code
+}
+
+func TestConversationsJSON(t *testing.T) {
+	out, errOut, code := run(t, "", "conversations", "--json")
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, errOut)
+	}
+	var convs []conversationJSON
+	if err := json.Unmarshal([]byte(out), &convs); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if len(convs) == 0 || convs[0].Title != "Karl Havoc" || convs[0].UnreadCount != 2 {
+		t.Errorf("unexpected conversations: %+v", convs)
+	}
+	if len(convs[0].Participants) != 1 {
+		t.Errorf("expected the user to be left out of participants, got %v", convs[0].Participants)
+	}
+}
+
+func TestMessagesLimitAndOrder(t *testing.T) {
+	out, errOut, code := run(t, "", "messages", "karl", "--limit=2")
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, errOut)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 messages, got %d:\n%s", len(lines), out)
+	}
+	if !strings.Contains(lines[1], "Do you think they know I'm just a guy?") {
+		t.Errorf("expected the newest message last, got:\n%s", out)
+	}
+}
+
+func TestSendFromStdin(t *testing.T) {
+	out, errOut, code := run(t, "On my way\n", "send", "Tammy Craps", "--json")
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, errOut)
+	}
+	var msg messageJSON
+	if err := json.Unmarshal([]byte(out), &msg); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if msg.Body != "On my way" || !msg.IsOwn || msg.ConversationID != "urn:li:conversation:conv-tammy" {
+		t.Errorf("unexpected sent message: %+v", msg)
+	}
+
+	if _, errOut, code := run(t, "  \n", "send", "tammy"); code != 1 || !strings.Contains(errOut, "empty") {
+		t.Errorf("expected an empty message to fail, got exit %d: %s", code, errOut)
+	}
+}
+
+func TestMarkRead(t *testing.T) {
+	out, errOut, code := run(t, "", "mark-read", "urn:li:conversation:conv-karl")
+	if code != 0 || out != "Marked Karl Havoc as read\n" {
+		t.Errorf("exit %d, stdout %q, stderr %q", code, out, errOut)
+	}
+}
+
+func TestConversationResolution(t *testing.T) {
+	if _, errOut, code := run(t, "", "messages", "a"); code != 1 || !strings.Contains(errOut, "matches several") {
+		t.Errorf("expected an ambiguous name to fail, got exit %d: %s", code, errOut)
+	}
+	if _, errOut, code := run(t, "", "messages", "nobody"); code != 1 || !strings.Contains(errOut, "no conversation") {
+		t.Errorf("expected an unknown name to fail, got exit %d: %s", code, errOut)
+	}
+}
+
+// pagedClient serves the demo inbox, plus an older page holding one more
+// conversation.
+type pagedClient struct {
+	*linkedin.DemoClient
+	older linkedin.DisplayConversation
+	pages int
+}
+
+func (c *pagedClient) FetchConversationsBefore(time.Time) tea.Cmd {
+	c.pages++
+	page := []linkedin.DisplayConversation{c.older}
+	if c.pages > 1 {
+		page = nil
+	}
+	return func() tea.Msg { return linkedin.ConversationsLoadedMsg{Conversations: page, Older: true} }
+}
+
+func TestResolveOlderConversation(t *testing.T) {
+	urn := linkedingo.NewURN("urn:li:msg_conversation:(urn:li:fsd_profile:me,2-old)")
+	client := &pagedClient{
+		DemoClient: linkedin.NewDemoClient(),
+		older:      linkedin.DisplayConversation{ID: urn.String(), URN: urn, Title: "Old Friend"},
+	}
+	e := &env{client: linkedin.NewSync(client)}
+
+	if c, err := e.resolveConversation("old friend"); err != nil || c.ID != urn.String() {
+		t.Errorf("expected a name past the first page to resolve, got %q, %v", c.ID, err)
+	}
+
+	client.pages = 0
+	for _, query := range []string{urn.String(), urn.ID()} {
+		if c, err := e.resolveConversation(query); err != nil || c.URN != urn {
+			t.Errorf("resolveConversation(%q) = %q, %v", query, c.URN, err)
+		}
+	}
+	if client.pages != 0 {
+		t.Errorf("expected an ID to resolve without paging, paged %d times", client.pages)
+	}
+
+	if _, err := e.resolveConversation("nobody"); err == nil || !strings.Contains(err.Error(), "no conversation") {
+		t.Errorf("expected an unknown name to fail once history runs out, got %v", err)
+	}
+}
+
+func TestUsageErrors(t *testing.T) {
+	tests := [][]string{
+		{"messages"},
+		{"conversations", "extra"},
+		{"messages", "karl", "--limit=0"},
+		{"send", "karl", "--nope"},
+		{"export", "--since=last-tuesday"},
+		{"export", "--format=pdf"},
+	}
+	for _, args := range tests {
+		if _, _, code := run(t, "", args...); code != 2 {
+			t.Errorf("%v: expected exit 2, got %d", args, code)
+		}
+	}
+}
+
+func TestExportToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "karl.md")
+	_, errOut, code := run(t, "", "export", "karl", "--output="+path)
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, errOut)
+	}
+	if !strings.Contains(errOut, "Exported 1 conversations") {
+		t.Errorf("expected a summary on stderr, got %q", errOut)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("expected the export to be private, got %v", perm)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(data), "# Karl Havoc\n") {
+		t.Errorf("expected a Markdown transcript, got:\n%s", data)
+	}
+}
+
+func TestExportFilters(t *testing.T) {
+	out, errOut, code := run(t, "", "export", "--format=json", "--participant=tammy")
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, errOut)
+	}
+	var got []struct {
+		Conversation struct{ Title string }
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(got) != 1 || got[0].Conversation.Title != "Tammy Craps" {
+		t.Errorf("expected only Tammy's conversation, got %+v", got)
+	}
+
+	if out, _, code := run(t, "", "export", "--format=json", "--until=2000-01-01"); code != 0 || strings.TrimSpace(out) != "[]" {
+		t.Errorf("expected nothing before 2000, got exit %d: %s", code, out)
+	}
+}
+
+func TestProfileFlag(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Cleanup(func() { _ = config.SetProfile(config.DefaultProfile) })
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"conversations", "--profile=work"}, strings.NewReader(""), &stdout, &stderr)
+	if code != 1 || !strings.Contains(stderr.String(), "profile work is not signed in") {
+		t.Errorf("expected exit 1 naming the profile, got %d: %s", code, stderr.String())
+	}
+
+	stderr.Reset()
+	if code := Run([]string{"conversations", "--profile=../x"}, strings.NewReader(""), &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit 2 for a bad profile name, got %d: %s", code, stderr.String())
+	}
+}
+
+func TestEncryptedCredentialsNeedPassphrase(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(passphraseEnv, "")
+	t.Cleanup(func() { _ = config.UseCredentialBackend(config.CredentialsConfig{}) })
+
+	dir := filepath.Join(home, ".config", "endorse")
+	_ = os.MkdirAll(dir, 0700)
+	_ = os.WriteFile(filepath.Join(dir, "config.toml"), []byte("[credentials]\nbackend = \"encrypted\"\n"), 0600)
+	_ = os.WriteFile(filepath.Join(dir, "credentials.json"), []byte(`{"cookie":"li_at=abc"}`), 0600)
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"conversations"}, strings.NewReader(""), &stdout, &stderr)
+	if code != 1 || !strings.Contains(stderr.String(), passphraseEnv) {
+		t.Errorf("expected exit 1 asking for %s, got %d: %s", passphraseEnv, code, stderr.String())
+	}
+	if _, err := os.Stat(filepath.Join(dir, "credentials.json")); err != nil {
+		t.Error("expected the plaintext file to be left alone without a passphrase")
+	}
+}
+
+func TestAuthImport(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	var cookies []string
+	orig := newClient
+	t.Cleanup(func() { newClient = orig })
+	newClient = func(_ context.Context, creds config.Credentials, _ linkedingo.URN) (linkedin.MessagingClient, error) {
+		cookies = append(cookies, creds.Cookie)
+		return linkedin.NewDemoClient(), nil
+	}
+
+	cookiesTxt := "#HttpOnly_.www.linkedin.com\tTRUE\t/\tTRUE\t0\tli_at\tAQEDtoken\n"
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"auth", "import", "-", "--demo"}, strings.NewReader(cookiesTxt), &stdout, &stderr)
+	if code != 1 || !strings.Contains(stderr.String(), "--demo") {
+		t.Errorf("expected --demo refused, got %d: %s", code, stderr.String())
+	}
+	if _, err := os.Stat(filepath.Join(home, ".config", "endorse", "credentials.json")); !os.IsNotExist(err) {
+		t.Errorf("expected nothing saved under --demo, got %v", err)
+	}
+
+	stderr.Reset()
+	code = Run([]string{"auth", "import", "-"}, strings.NewReader(cookiesTxt), &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Signed in as") {
+		t.Errorf("expected a confirmation, got %q", stdout.String())
+	}
+
+	data, err := os.ReadFile(filepath.Join(home, ".config", "endorse", "credentials.json"))
+	if err != nil || !strings.Contains(string(data), "li_at=AQEDtoken") {
+		t.Errorf("expected the imported cookie saved, got %s (%v)", data, err)
+	}
+	if len(cookies) == 0 || !strings.Contains(cookies[0], "li_at=AQEDtoken") {
+		t.Errorf("expected the imported cookie to be validated before saving, got %q", cookies)
+	}
+
+	stderr.Reset()
+	code = Run([]string{"auth", "import", "-"}, strings.NewReader("# empty\n"), &stdout, &stderr)
+	if code != 1 || !strings.Contains(stderr.String(), "no li_at cookie") {
+		t.Errorf("expected exit 1 for an export without a session, got %d: %s", code, stderr.String())
+	}
+
+	if code := Run([]string{"auth", "login"}, strings.NewReader(""), &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit 2 for an unknown auth action, got %d", code)
+	}
+}
+
+func TestSignInRebuildsClientWithURN(t *testing.T) {
+	var urns []linkedingo.URN
+	orig := newClient
+	t.Cleanup(func() { newClient = orig })
+	newClient = func(_ context.Context, _ config.Credentials, urn linkedingo.URN) (linkedin.MessagingClient, error) {
+		urns = append(urns, urn)
+		return linkedin.NewDemoClient(), nil
+	}
+
+	if _, name, err := signIn(config.Credentials{Cookie: "li_at=abc"}); err != nil || name != "Demo User" {
+		t.Fatalf("signIn() = %q, %v", name, err)
+	}
+	// Without the URN, linkedingo queries an empty mailbox
+	if len(urns) != 2 || !urns[0].IsEmpty() || urns[1].IsEmpty() {
+		t.Errorf("expected a client without a URN to validate, then one built with it, got %q", urns)
+	}
+}
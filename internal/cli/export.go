@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/ggfevans/endorse/internal/export"
+	"github.com/ggfevans/endorse/internal/linkedin"
+)
+
+// runExport writes the full history of the named conversations, or of every
+// conversation, to stdout or --output.
+func runExport(e *env, args []string) error {
+	var convs []linkedin.DisplayConversation
+	var err error
+	if len(args) == 0 {
+		if convs, err = e.allConversations(); err != nil {
+			return err
+		}
+	}
+	for _, arg := range args {
+		conv, err := e.resolveConversation(arg)
+		if err != nil {
+			return err
+		}
+		convs = append(convs, conv)
+	}
+
+	filter := export.Filter{Since: e.opts.since, Until: e.opts.until, Participant: e.opts.participant}
+	transcripts, err := export.Collect(e.client, convs, filter)
+	if err != nil {
+		return err
+	}
+
+	if e.opts.output == "" || e.opts.output == "-" {
+		return export.Write(e.stdout, e.opts.format, transcripts)
+	}
+
+	if err := export.WriteFile(e.opts.output, e.opts.format, transcripts); err != nil {
+		return err
+	}
+	printSummary(e.stderr, transcripts, e.opts.output)
+	return nil
+}
+
+// allConversations pages back through the inbox until it runs out or passes
+// --since.
+func (e *env) allConversations() ([]linkedin.DisplayConversation, error) {
+	convs, err := e.client.Conversations()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(convs))
+	for _, c := range convs {
+		seen[c.ID] = true
+	}
+	for len(convs) > 0 {
+		oldest := convs[len(convs)-1].LastActivityAt
+		if !e.opts.since.IsZero() && oldest.Before(e.opts.since) {
+			break
+		}
+		page, err := e.client.ConversationsBefore(oldest)
+		if err != nil {
+			return nil, err
+		}
+		added := 0
+		for _, c := range page {
+			if !seen[c.ID] {
+				seen[c.ID] = true
+				convs = append(convs, c)
+				added++
+			}
+		}
+		if added == 0 {
+			break
+		}
+	}
+	return convs, nil
+}
+
+// printSummary reports what an export to a file contained.
+func printSummary(w io.Writer, transcripts []export.Transcript, path string) {
+	msgs := 0
+	for _, t := range transcripts {
+		msgs += len(t.Messages)
+	}
+	fmt.Fprintf(w, "Exported %d conversations (%d messages) to %s\n", len(transcripts), msgs, path)
+}
@@ -18,17 +18,37 @@ func (c Credentials) IsEmpty() bool {
 	return c.Cookie == ""
 }
 
-// CredentialsPath returns the path to the credentials file.
+// CredentialsPath returns the path to the active profile's plaintext
+// credentials file.
 func CredentialsPath() (string, error) {
-	dir, err := ConfigDir()
+	dir, err := DataDir()
 	if err != nil {
 		return "", err
 	}
 	return filepath.Join(dir, "credentials.json"), nil
 }
 
-// LoadCredentials reads stored credentials from disk.
+// LoadCredentials reads the active profile's credentials from the
+// configured backend. Nothing stored yields empty credentials.
 func LoadCredentials() (Credentials, error) {
+	return credentialStore().Load()
+}
+
+// SaveCredentials stores the active profile's credentials in the configured
+// backend.
+func SaveCredentials(creds Credentials) error {
+	return credentialStore().Save(creds)
+}
+
+// ClearCredentials removes the active profile's stored credentials.
+func ClearCredentials() error {
+	return credentialStore().Clear()
+}
+
+// plaintextStore keeps credentials as JSON in a file only the user can read.
+type plaintextStore struct{}
+
+func (plaintextStore) Load() (Credentials, error) {
 	var creds Credentials
 
 	path, err := CredentialsPath()
@@ -51,9 +71,8 @@ func LoadCredentials() (Credentials, error) {
 	return creds, nil
 }
 
-// SaveCredentials writes credentials to disk with restricted permissions.
-func SaveCredentials(creds Credentials) error {
-	dir, err := ConfigDir()
+func (plaintextStore) Save(creds Credentials) error {
+	dir, err := DataDir()
 	if err != nil {
 		return err
 	}
@@ -71,8 +90,7 @@ func SaveCredentials(creds Credentials) error {
 	return os.WriteFile(path, data, 0600)
 }
 
-// ClearCredentials removes stored credentials.
-func ClearCredentials() error {
+func (plaintextStore) Clear() error {
 	path, err := CredentialsPath()
 	if err != nil {
 		return err
@@ -9,13 +9,62 @@ import (
 
 // Config holds all application configuration.
 type Config struct {
-	ThemeName string `toml:"theme"`
+	ThemeName string        `toml:"theme"` // "" picks a dark or light theme to suit the terminal
+	Cache     CacheConfig   `toml:"cache"`
+	Privacy   PrivacyConfig `toml:"privacy"`
+	Display   DisplayConfig `toml:"display"`
+	Export    ExportConfig  `toml:"export"`
+
+	Credentials CredentialsConfig `toml:"credentials"`
+	Keys        KeyMap            `toml:"keys"`
+}
+
+// CacheConfig controls the on-disk message cache.
+type CacheConfig struct {
+	Enabled     bool `toml:"enabled"`
+	MaxAgeDays  int  `toml:"max_age_days"`          // drop conversations idle longer than this (0 = keep forever)
+	MaxMessages int  `toml:"max_messages_per_conv"` // newest messages kept per conversation (0 = unlimited)
+}
+
+// PrivacyConfig controls what endorse reveals to the people you talk to.
+type PrivacyConfig struct {
+	SendTyping bool `toml:"send_typing"` // show others when you are typing
+}
+
+// DisplayConfig controls how messages are presented.
+type DisplayConfig struct {
+	AbsoluteTimes   bool `toml:"absolute_times"`    // clock times instead of "5m ago" (toggle with t)
+	GroupGapMinutes int  `toml:"group_gap_minutes"` // silence that splits a sender's messages into groups (0 = never)
+	HighContrast    bool `toml:"high_contrast"`     // no colors; bold, underline and reverse video only (also set by NO_COLOR)
+}
+
+// ExportConfig controls exports started from the TUI.
+type ExportConfig struct {
+	Format string `toml:"format"` // markdown, json or mbox
+	Dir    string `toml:"dir"`    // where exports are written ("" = ~/.config/endorse/exports)
 }
 
 // DefaultConfig returns the default configuration.
 func DefaultConfig() Config {
 	return Config{
-		ThemeName: "dracula",
+		Cache: CacheConfig{
+			Enabled:     true,
+			MaxAgeDays:  90,
+			MaxMessages: 200,
+		},
+		Privacy: PrivacyConfig{
+			SendTyping: true,
+		},
+		Display: DisplayConfig{
+			GroupGapMinutes: 30,
+		},
+		Export: ExportConfig{
+			Format: "markdown",
+		},
+		Credentials: CredentialsConfig{
+			Backend: BackendPlaintext,
+		},
+		Keys: DefaultKeyMap(),
 	}
 }
 
@@ -50,6 +99,13 @@ func Load() (Config, error) {
 		return cfg, err
 	}
 
+	// Bindings in the file replace the defaults action by action
+	cfg.Keys.normalize()
+	if err := cfg.Keys.Validate(); err != nil {
+		cfg.Keys = DefaultKeyMap()
+		return cfg, err
+	}
+
 	return cfg, nil
 }
 
@@ -73,3 +129,28 @@ func (c Config) Save() error {
 
 	return toml.NewEncoder(f).Encode(c)
 }
+
+// WriteFile replaces a file's contents atomically, readable only by the
+// user. Data goes to a uniquely named temporary file beside it first, so
+// concurrent writers never share one, and is then renamed into place.
+func WriteFile(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,473 @@
+package config
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Credential backends, chosen with backend in the [credentials] section.
+const (
+	BackendPlaintext = "plaintext" // JSON file readable by the user only
+	BackendEncrypted = "encrypted" // file sealed with a passphrase
+	BackendCommand   = "command"   // external tool such as pass
+)
+
+var (
+	// ErrLocked means credentials are stored encrypted and no passphrase
+	// has been given for the active profile.
+	ErrLocked = errors.New("saved credentials are encrypted; a passphrase is needed to unlock them")
+
+	// ErrWrongPassphrase means the passphrase doesn't decrypt the stored
+	// credentials.
+	ErrWrongPassphrase = errors.New("wrong passphrase")
+
+	// ErrReadOnly means the command backend has nowhere to save to.
+	ErrReadOnly = errors.New("credentials can't be saved: the command backend has no store_command")
+)
+
+// CredentialsConfig chooses where credentials are kept.
+type CredentialsConfig struct {
+	Backend      string `toml:"backend"`       // plaintext, encrypted or command
+	Command      string `toml:"command"`       // command backend: prints the credentials, e.g. "pass show linkedin"
+	StoreCommand string `toml:"store_command"` // command backend: saves credentials read from stdin ("" = read-only)
+}
+
+// CredentialStore keeps the active profile's credentials.
+type CredentialStore interface {
+	Load() (Credentials, error)
+	Save(creds Credentials) error
+	Clear() error
+}
+
+var (
+	storeMu     sync.RWMutex
+	store       CredentialStore = plaintextStore{}
+	backendName                 = BackendPlaintext
+
+	// Passphrases given this session, and the keys derived from them, by
+	// profile
+	passphrases = map[string]string{}
+	keys        = map[string]derivedKey{}
+)
+
+// UseCredentialBackend selects the backend that LoadCredentials,
+// SaveCredentials and ClearCredentials go through. A bad configuration is
+// returned as an error and also makes every later call fail, rather than
+// quietly falling back to plaintext.
+func UseCredentialBackend(c CredentialsConfig) error {
+	var s CredentialStore
+	var err error
+	switch c.Backend {
+	case "", BackendPlaintext:
+		c.Backend = BackendPlaintext
+		s = plaintextStore{}
+	case BackendEncrypted:
+		s = encryptedStore{}
+	case BackendCommand:
+		if strings.TrimSpace(c.Command) == "" {
+			err = errors.New("credentials backend \"command\" needs a command")
+		}
+		s = commandStore{load: c.Command, store: c.StoreCommand}
+	default:
+		err = fmt.Errorf("unknown credentials backend %q (want plaintext, encrypted or command)", c.Backend)
+	}
+	if err != nil {
+		s = brokenStore{err}
+	}
+
+	storeMu.Lock()
+	store, backendName = s, c.Backend
+	storeMu.Unlock()
+	return err
+}
+
+// CredentialBackend returns the name of the backend in use.
+func CredentialBackend() string {
+	storeMu.RLock()
+	defer storeMu.RUnlock()
+	return backendName
+}
+
+func credentialStore() CredentialStore {
+	storeMu.RLock()
+	defer storeMu.RUnlock()
+	return store
+}
+
+// SetPassphrase sets the passphrase that encrypts the active profile's
+// credentials for the rest of the session. An empty passphrase forgets it.
+// Either way the key derived from the previous one is dropped.
+func SetPassphrase(p string) {
+	profile := ActiveProfile()
+	storeMu.Lock()
+	defer storeMu.Unlock()
+	delete(keys, profile)
+	if p == "" {
+		delete(passphrases, profile)
+	} else {
+		passphrases[profile] = p
+	}
+}
+
+// HasPassphrase reports whether a passphrase is set for the active profile.
+func HasPassphrase() bool {
+	_, ok := passphrase()
+	return ok
+}
+
+func passphrase() (string, bool) {
+	profile := ActiveProfile()
+	storeMu.RLock()
+	defer storeMu.RUnlock()
+	p, ok := passphrases[profile]
+	return p, ok
+}
+
+// NeedsMigration reports whether the active profile has a plaintext
+// credentials file that the configured backend should take over.
+func NeedsMigration() bool {
+	switch s := credentialStore().(type) {
+	case encryptedStore:
+	case commandStore:
+		if s.store == "" {
+			return false
+		}
+	default:
+		return false
+	}
+	path, err := CredentialsPath()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// MigrateCredentials moves plaintext credentials into the configured
+// backend, then deletes the plaintext file. The encrypted backend needs a
+// passphrase set first.
+func MigrateCredentials() error {
+	if _, ok := credentialStore().(plaintextStore); ok {
+		return nil
+	}
+	creds, err := plaintextStore{}.Load()
+	if err != nil {
+		return err
+	}
+	if !creds.IsEmpty() {
+		if err := SaveCredentials(creds); err != nil {
+			return err
+		}
+	}
+	return plaintextStore{}.Clear()
+}
+
+// brokenStore fails every call with the configuration error.
+type brokenStore struct{ err error }
+
+func (s brokenStore) Load() (Credentials, error) { return Credentials{}, s.err }
+func (s brokenStore) Save(Credentials) error     { return s.err }
+func (s brokenStore) Clear() error               { return s.err }
+
+// --- Encrypted file ---
+
+// Argon2id parameters for new files, the second recommended option in
+// RFC 9106. They are stored alongside the ciphertext, so they can be raised
+// later without breaking existing files.
+const (
+	argonTime    = 3
+	argonMemory  = 64 * 1024 // KiB
+	argonThreads = 4
+	keyLen       = 32 // AES-256
+	saltLen      = 16
+)
+
+// deriveKey is Argon2id, swapped out in tests to count derivations.
+var deriveKey = argon2.IDKey
+
+// sealedVersion marks the file format, and is also bound into the AEAD so
+// the header can't be swapped.
+const sealedVersion = 1
+
+// sealedCredentials is the on-disk form of encrypted credentials.
+type sealedCredentials struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Time       uint32 `json:"time"`
+	Memory     uint32 `json:"memory"`
+	Threads    uint8  `json:"threads"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// EncryptedCredentialsPath returns the path to the active profile's
+// encrypted credentials file.
+func EncryptedCredentialsPath() (string, error) {
+	dir, err := DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "credentials.enc"), nil
+}
+
+// encryptedStore seals credentials with AES-256-GCM under a key derived
+// from the profile's passphrase with Argon2id.
+type encryptedStore struct{}
+
+func (encryptedStore) Load() (Credentials, error) {
+	path, err := EncryptedCredentialsPath()
+	if err != nil {
+		return Credentials{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Credentials{}, nil
+		}
+		return Credentials{}, err
+	}
+
+	pass, ok := passphrase()
+	if !ok {
+		return Credentials{}, ErrLocked
+	}
+	return openCredentials(data, pass)
+}
+
+func (encryptedStore) Save(creds Credentials) error {
+	pass, ok := passphrase()
+	if !ok {
+		return errors.New("no passphrase set to encrypt credentials with")
+	}
+	data, err := sealCredentials(creds, pass)
+	if err != nil {
+		return err
+	}
+
+	path, err := EncryptedCredentialsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return WriteFile(path, data)
+}
+
+func (encryptedStore) Clear() error {
+	path, err := EncryptedCredentialsPath()
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func sealCredentials(creds Credentials, pass string) ([]byte, error) {
+	plain, err := json.Marshal(creds)
+	if err != nil {
+		return nil, err
+	}
+
+	s := sealedCredentials{
+		Version: sealedVersion,
+		KDF:     "argon2id",
+		Time:    argonTime,
+		Memory:  argonMemory,
+		Threads: argonThreads,
+	}
+	// Keep the salt of a key already derived from this passphrase, so saving
+	// doesn't derive another; the nonce is still fresh every time
+	if k, ok := cachedKey(); ok && k.pass == pass && k.time == s.Time && k.memory == s.Memory && k.threads == s.Threads {
+		s.Salt = k.salt
+	} else {
+		s.Salt = make([]byte, saltLen)
+		if _, err := rand.Read(s.Salt); err != nil {
+			return nil, err
+		}
+	}
+	aead, err := s.aead(pass)
+	if err != nil {
+		return nil, err
+	}
+	s.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(s.Nonce); err != nil {
+		return nil, err
+	}
+	s.Ciphertext = aead.Seal(nil, s.Nonce, plain, s.additionalData())
+	return json.MarshalIndent(s, "", "  ")
+}
+
+func openCredentials(data []byte, pass string) (Credentials, error) {
+	var creds Credentials
+	var s sealedCredentials
+	if err := json.Unmarshal(data, &s); err != nil {
+		return creds, fmt.Errorf("reading encrypted credentials: %w", err)
+	}
+	if s.Version != sealedVersion || s.KDF != "argon2id" {
+		return creds, fmt.Errorf("encrypted credentials use an unsupported format (version %d, %s)", s.Version, s.KDF)
+	}
+
+	aead, err := s.aead(pass)
+	if err != nil {
+		return creds, err
+	}
+	if len(s.Nonce) != aead.NonceSize() {
+		return creds, errors.New("encrypted credentials are damaged")
+	}
+	plain, err := aead.Open(nil, s.Nonce, s.Ciphertext, s.additionalData())
+	if err != nil {
+		return creds, ErrWrongPassphrase
+	}
+	if err := json.Unmarshal(plain, &creds); err != nil {
+		return creds, err
+	}
+	return creds, nil
+}
+
+// derivedKey is a key derived from a profile's passphrase, with the salt and
+// parameters it was derived for.
+type derivedKey struct {
+	pass    string
+	salt    []byte
+	time    uint32
+	memory  uint32
+	threads uint8
+	key     []byte
+}
+
+func cachedKey() (derivedKey, bool) {
+	profile := ActiveProfile()
+	storeMu.RLock()
+	defer storeMu.RUnlock()
+	k, ok := keys[profile]
+	return k, ok
+}
+
+// key returns the key for the file's parameters. Argon2id is slow on
+// purpose, so the key is kept for the active profile and only derived again
+// when the passphrase, salt or parameters change: once per unlock rather
+// than on every load and save.
+func (s sealedCredentials) key(pass string) []byte {
+	if k, ok := cachedKey(); ok && k.pass == pass && bytes.Equal(k.salt, s.Salt) &&
+		k.time == s.Time && k.memory == s.Memory && k.threads == s.Threads {
+		return k.key
+	}
+
+	key := deriveKey([]byte(pass), s.Salt, s.Time, s.Memory, s.Threads, keyLen)
+	profile := ActiveProfile()
+	storeMu.Lock()
+	defer storeMu.Unlock()
+	// Only keep it if the passphrase wasn't changed while deriving
+	if passphrases[profile] == pass {
+		keys[profile] = derivedKey{pass: pass, salt: s.Salt, time: s.Time, memory: s.Memory, threads: s.Threads, key: key}
+	}
+	return key
+}
+
+// aead returns the cipher for the file's parameters.
+func (s sealedCredentials) aead(pass string) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(s.key(pass))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (s sealedCredentials) additionalData() []byte {
+	return fmt.Appendf(nil, "endorse-credentials v%d %s t=%d m=%d p=%d", s.Version, s.KDF, s.Time, s.Memory, s.Threads)
+}
+
+// --- External command ---
+
+// commandStore reads credentials from the output of a shell command, and
+// saves them by piping JSON to another. ENDORSE_PROFILE is set for both, so
+// one command can serve several profiles.
+type commandStore struct {
+	load  string
+	store string
+}
+
+// Load accepts either the credentials as JSON or, as password managers
+// print it, just the cookie on the first line.
+func (s commandStore) Load() (Credentials, error) {
+	var creds Credentials
+	out, err := runShell(s.load, nil)
+	if err != nil {
+		return creds, err
+	}
+
+	out = bytes.TrimSpace(out)
+	if len(out) == 0 {
+		return creds, nil
+	}
+	if out[0] == '{' {
+		if err := json.Unmarshal(out, &creds); err != nil {
+			return creds, fmt.Errorf("credentials command printed invalid JSON: %w", err)
+		}
+		return creds, nil
+	}
+	first, _, _ := strings.Cut(string(out), "\n")
+	creds.Cookie = strings.TrimSpace(first)
+	return creds, nil
+}
+
+func (s commandStore) Save(creds Credentials) error {
+	if s.store == "" {
+		return ErrReadOnly
+	}
+	data, err := json.Marshal(creds)
+	if err != nil {
+		return err
+	}
+	_, err = runShell(s.store, data)
+	return err
+}
+
+// Clear leaves the external store alone; it's managed with its own tool.
+func (commandStore) Clear() error {
+	return nil
+}
+
+// runShell runs a command line through the user's shell, feeding it stdin
+// and returning its output. Failures include what it wrote to stderr.
+func runShell(line string, stdin []byte) ([]byte, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", line)
+	} else {
+		cmd = exec.Command("sh", "-c", line)
+	}
+	cmd.Env = append(os.Environ(), "ENDORSE_PROFILE="+ActiveProfile())
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s: %w: %s", line, err, msg)
+		}
+		return nil, fmt.Errorf("%s: %w", line, err)
+	}
+	return out, nil
+}
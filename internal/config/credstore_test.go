@@ -0,0 +1,176 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// useBackend switches to a credential backend for one test.
+func useBackend(t *testing.T, c CredentialsConfig) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	if err := UseCredentialBackend(c); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = UseCredentialBackend(CredentialsConfig{})
+		SetPassphrase("")
+	})
+}
+
+var testCreds = Credentials{Cookie: "li_at=secret", XLiTrack: "track"}
+
+func TestEncryptedRoundTrip(t *testing.T) {
+	useBackend(t, CredentialsConfig{Backend: BackendEncrypted})
+
+	SetPassphrase("correct horse")
+	if err := SaveCredentials(testCreds); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	path, _ := EncryptedCredentialsPath()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "secret") {
+		t.Error("expected the cookie not to appear in the encrypted file")
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("expected mode 0600, got %v", info.Mode().Perm())
+	}
+
+	if got, err := LoadCredentials(); err != nil || got != testCreds {
+		t.Errorf("Load = %+v, %v; want %+v", got, err, testCreds)
+	}
+
+	SetPassphrase("")
+	if _, err := LoadCredentials(); !errors.Is(err, ErrLocked) {
+		t.Errorf("expected ErrLocked without a passphrase, got %v", err)
+	}
+	SetPassphrase("wrong")
+	if _, err := LoadCredentials(); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("expected ErrWrongPassphrase, got %v", err)
+	}
+}
+
+func TestEncryptedKeyDerivedOncePerUnlock(t *testing.T) {
+	useBackend(t, CredentialsConfig{Backend: BackendEncrypted})
+	derived := 0
+	t.Cleanup(func() { deriveKey = argon2.IDKey })
+	deriveKey = func(pass, salt []byte, time, memory uint32, threads uint8, keyLen uint32) []byte {
+		derived++
+		return argon2.IDKey(pass, salt, time, memory, threads, keyLen)
+	}
+
+	SetPassphrase("correct horse")
+	for range 2 {
+		if err := SaveCredentials(testCreds); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+		if _, err := LoadCredentials(); err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+	}
+	if derived != 1 {
+		t.Errorf("expected one key derivation for repeated saves and loads, got %d", derived)
+	}
+
+	// Unlocking again, as after a restart, derives it once more
+	SetPassphrase("")
+	SetPassphrase("correct horse")
+	if got, err := LoadCredentials(); err != nil || got != testCreds {
+		t.Errorf("Load = %+v, %v; want %+v", got, err, testCreds)
+	}
+	if _, err := LoadCredentials(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if derived != 2 {
+		t.Errorf("expected one more derivation after unlocking again, got %d", derived-1)
+	}
+
+	SetPassphrase("wrong")
+	if _, err := LoadCredentials(); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("expected ErrWrongPassphrase with a cached key for another passphrase, got %v", err)
+	}
+}
+
+func TestMigratePlaintextToEncrypted(t *testing.T) {
+	useBackend(t, CredentialsConfig{Backend: BackendEncrypted})
+	if err := (plaintextStore{}).Save(testCreds); err != nil {
+		t.Fatal(err)
+	}
+
+	if !NeedsMigration() {
+		t.Fatal("expected the plaintext file to need migrating")
+	}
+	SetPassphrase("pw")
+	if err := MigrateCredentials(); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	if NeedsMigration() {
+		t.Error("expected the plaintext file to be gone")
+	}
+	if got, err := LoadCredentials(); err != nil || got != testCreds {
+		t.Errorf("Load after migration = %+v, %v", got, err)
+	}
+}
+
+func TestCommandBackend(t *testing.T) {
+	if _, err := os.Stat("/bin/sh"); err != nil {
+		t.Skip("needs a POSIX shell")
+	}
+	store := filepath.Join(t.TempDir(), "store.json")
+	useBackend(t, CredentialsConfig{
+		Backend:      BackendCommand,
+		Command:      "cat " + store + " 2>/dev/null || true",
+		StoreCommand: "cat > " + store,
+	})
+
+	if got, err := LoadCredentials(); err != nil || !got.IsEmpty() {
+		t.Errorf("expected no credentials before saving, got %+v, %v", got, err)
+	}
+	if err := SaveCredentials(testCreds); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if got, err := LoadCredentials(); err != nil || got != testCreds {
+		t.Errorf("Load = %+v, %v; want %+v", got, err, testCreds)
+	}
+}
+
+func TestCommandBackendBareCookie(t *testing.T) {
+	if _, err := os.Stat("/bin/sh"); err != nil {
+		t.Skip("needs a POSIX shell")
+	}
+	useBackend(t, CredentialsConfig{
+		Backend: BackendCommand,
+		Command: `printf 'li_at=%s\nurl: linkedin.com\n' "$ENDORSE_PROFILE"`,
+	})
+
+	got, err := LoadCredentials()
+	if err != nil || got.Cookie != "li_at=default" {
+		t.Errorf("expected the first line as the cookie, got %+v, %v", got, err)
+	}
+	if err := SaveCredentials(testCreds); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly without a store command, got %v", err)
+	}
+	if NeedsMigration() {
+		t.Error("a read-only backend can't take over a plaintext file")
+	}
+}
+
+func TestUnknownBackendFails(t *testing.T) {
+	t.Cleanup(func() { _ = UseCredentialBackend(CredentialsConfig{}) })
+	if err := UseCredentialBackend(CredentialsConfig{Backend: "keyring"}); err == nil {
+		t.Fatal("expected an error for an unknown backend")
+	}
+	if _, err := LoadCredentials(); err == nil {
+		t.Error("expected loads to fail rather than fall back to plaintext")
+	}
+}
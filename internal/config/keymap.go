@@ -0,0 +1,251 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Actions that can be bound in the [keys] section.
+const (
+	ActionQuit          = "quit"
+	ActionNextPanel     = "next_panel"
+	ActionPrevPanel     = "prev_panel"
+	ActionSwitchAccount = "switch_account"
+	ActionNextTheme     = "next_theme"
+	ActionHelp          = "help"
+	ActionPalette       = "palette"
+	ActionDown          = "down"
+	ActionUp            = "up"
+	ActionTop           = "top"
+	ActionBottom        = "bottom"
+	ActionPageDown      = "page_down"
+	ActionPageUp        = "page_up"
+	ActionOpen          = "open"
+	ActionReply         = "reply"
+	ActionFilter        = "filter"
+	ActionSearch        = "search"
+	ActionMarkRead      = "mark_read"
+	ActionDelete        = "delete"
+	ActionPin           = "pin"
+	ActionMute          = "mute"
+	ActionTimeFormat    = "time_format"
+	ActionExport        = "export"
+	ActionRetry         = "retry"
+	ActionDiscard       = "discard"
+	ActionBack          = "back"
+	ActionSend          = "send"
+	ActionConfirm       = "confirm"
+	ActionCancel        = "cancel"
+)
+
+// Key contexts: where an action's keys are listened for. Two actions can
+// share a key only if they never share a context.
+const (
+	ContextSidebar = "sidebar"
+	ContextList    = "conversation list"
+	ContextThread  = "thread"
+	ContextCompose = "compose"
+	ContextConfirm = "confirm dialog"
+)
+
+// Action describes a bindable action.
+type Action struct {
+	Name     string
+	Desc     string
+	Keys     []string // default keys
+	Contexts []string
+}
+
+var (
+	panels           = []string{ContextSidebar, ContextList, ContextThread}
+	panelsAndCompose = []string{ContextSidebar, ContextList, ContextThread, ContextCompose}
+)
+
+// Actions lists every bindable action with its default keys.
+var Actions = []Action{
+	{ActionQuit, "Quit", []string{"q"}, panels},
+	{ActionNextPanel, "Next panel", []string{"tab", "right"}, panelsAndCompose},
+	{ActionPrevPanel, "Previous panel", []string{"shift+tab", "left"}, panelsAndCompose},
+	{ActionSwitchAccount, "Switch account (profile)", []string{"A"}, panels},
+	{ActionNextTheme, "Switch to the next theme", []string{"T"}, panels},
+	{ActionHelp, "Show all key bindings", []string{"?"}, panels},
+	{ActionPalette, "Open the command palette", []string{"ctrl+p"}, panels},
+	{ActionDown, "Move down", []string{"j", "down"}, panels},
+	{ActionUp, "Move up", []string{"k", "up"}, panels},
+	{ActionTop, "Jump to top", []string{"g"}, []string{ContextList}},
+	{ActionBottom, "Jump to bottom", []string{"G"}, []string{ContextList}},
+	{ActionPageDown, "Scroll half a page down", []string{"ctrl+d", "pgdown"}, []string{ContextThread}},
+	{ActionPageUp, "Scroll half a page up", []string{"ctrl+u", "pgup"}, []string{ContextThread}},
+	{ActionOpen, "Open conversation or folder", []string{"enter"}, []string{ContextSidebar, ContextList}},
+	{ActionReply, "Reply / compose", []string{"r"}, []string{ContextList, ContextThread}},
+	{ActionFilter, "Switch between Inbox and Unread", []string{"f"}, []string{ContextList}},
+	{ActionSearch, "Search conversations and messages", []string{"/"}, []string{ContextList}},
+	{ActionMarkRead, "Toggle read/unread", []string{"m"}, []string{ContextList}},
+	{ActionDelete, "Delete conversation", []string{"d"}, []string{ContextList}},
+	{ActionPin, "Pin / unpin conversation", []string{"p"}, []string{ContextList}},
+	{ActionMute, "Mute / unmute conversation", []string{"M"}, []string{ContextList}},
+	{ActionTimeFormat, "Switch between relative and absolute timestamps", []string{"t"}, []string{ContextList, ContextThread}},
+	{ActionExport, "Export conversation", []string{"e"}, []string{ContextList, ContextThread}},
+	{ActionRetry, "Retry unsent messages now", []string{"R"}, []string{ContextThread}},
+	{ActionDiscard, "Discard newest unsent message", []string{"X"}, []string{ContextThread}},
+	{ActionBack, "Back", []string{"esc"}, []string{ContextThread, ContextCompose}},
+	{ActionSend, "Send message", []string{"enter", "ctrl+s"}, []string{ContextCompose}},
+	{ActionConfirm, "Confirm", []string{"enter"}, []string{ContextConfirm}},
+	{ActionCancel, "Cancel", []string{"esc"}, []string{ContextConfirm}},
+}
+
+// KeyMap binds actions to keys, named as Bubble Tea reports them: "j",
+// "G", "ctrl+d", "shift+tab", "enter", "esc", "pgdown" and so on. An empty
+// list unbinds an action.
+type KeyMap map[string][]string
+
+// DefaultKeyMap returns the default bindings.
+func DefaultKeyMap() KeyMap {
+	k := make(KeyMap, len(Actions))
+	for _, a := range Actions {
+		k[a.Name] = slices.Clone(a.Keys)
+	}
+	return k
+}
+
+// Matches reports whether a key, as returned by tea.KeyMsg.String, is bound
+// to an action.
+func (k KeyMap) Matches(action, key string) bool {
+	return slices.Contains(k[action], key)
+}
+
+// Label returns how an action's first key is written in hints, or "" if
+// the action is unbound.
+func (k KeyMap) Label(action string) string {
+	if keys := k[action]; len(keys) > 0 {
+		return KeyLabel(keys[0])
+	}
+	return ""
+}
+
+// keyAliases are friendlier names accepted for keys Bubble Tea names
+// awkwardly.
+var keyAliases = map[string]string{
+	"space":    " ",
+	"escape":   "esc",
+	"return":   "enter",
+	"pagedown": "pgdown",
+	"pageup":   "pgup",
+}
+
+// normalize rewrites aliases to the names Bubble Tea uses.
+func (k KeyMap) normalize() {
+	for action, keys := range k {
+		for i, key := range keys {
+			if alias, ok := keyAliases[strings.ToLower(key)]; ok {
+				k[action][i] = alias
+			}
+		}
+	}
+}
+
+// namedKeys holds every key name Bubble Tea can report, besides single
+// characters.
+var namedKeys = func() map[string]bool {
+	names := make(map[string]bool)
+	for t := -256; t < 256; t++ {
+		if name := tea.KeyType(t).String(); name != "" {
+			names[name] = true
+		}
+	}
+	return names
+}()
+
+// validKey reports whether Bubble Tea can report a key by this name.
+func validKey(key string) bool {
+	key = strings.TrimPrefix(key, "alt+")
+	return namedKeys[key] || utf8.RuneCountInString(key) == 1
+}
+
+// Validate checks for unknown actions and keys, and for keys bound to two
+// actions that are listened for in the same place.
+func (k KeyMap) Validate() error {
+	known := make(map[string]Action, len(Actions))
+	for _, a := range Actions {
+		known[a.Name] = a
+	}
+
+	for action, keys := range k {
+		if _, ok := known[action]; !ok {
+			return fmt.Errorf("keys: unknown action %q", action)
+		}
+		for _, key := range keys {
+			switch {
+			case !validKey(key):
+				return fmt.Errorf("keys: %s: unknown key %q", action, key)
+			case key == "ctrl+c":
+				return fmt.Errorf("keys: %s: ctrl+c always quits and can't be rebound", action)
+			}
+		}
+	}
+
+	// Walk the actions in order so the error names the same pair every time
+	for _, ctx := range []string{ContextSidebar, ContextList, ContextThread, ContextCompose, ContextConfirm} {
+		bound := make(map[string]string)
+		for _, a := range Actions {
+			if !slices.Contains(a.Contexts, ctx) {
+				continue
+			}
+			for _, key := range k[a.Name] {
+				if other, ok := bound[key]; ok && other != a.Name {
+					return fmt.Errorf("keys: %q is bound to both %s and %s in the %s", key, other, a.Name, ctx)
+				}
+				bound[key] = a.Name
+				if ctx == ContextCompose && utf8.RuneCountInString(key) == 1 {
+					return fmt.Errorf("keys: %s: %q can't be used while composing, where it types text", a.Name, key)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// keyLabels are the display names of keys whose names are terse.
+var keyLabels = map[string]string{
+	"up":        "↑",
+	"down":      "↓",
+	"left":      "←",
+	"right":     "→",
+	"enter":     "Enter",
+	"esc":       "Esc",
+	"tab":       "Tab",
+	"shift+tab": "Shift+Tab",
+	"pgup":      "PgUp",
+	"pgdown":    "PgDn",
+	"home":      "Home",
+	"end":       "End",
+	"backspace": "Backspace",
+	"delete":    "Del",
+	" ":         "Space",
+}
+
+// KeyLabel returns how a key is written for people: "ctrl+d" is "Ctrl+D"
+// and "pgdown" is "PgDn". Single characters are left as they are.
+func KeyLabel(key string) string {
+	prefix := ""
+	if rest, ok := strings.CutPrefix(key, "alt+"); ok {
+		prefix, key = "Alt+", rest
+	}
+	if label, ok := keyLabels[key]; ok {
+		return prefix + label
+	}
+	if rest, ok := strings.CutPrefix(key, "ctrl+"); ok {
+		return prefix + "Ctrl+" + strings.ToUpper(rest)
+	}
+	if rest, ok := strings.CutPrefix(key, "shift+"); ok {
+		return prefix + "Shift+" + KeyLabel(rest)
+	}
+	if utf8.RuneCountInString(key) > 1 {
+		return prefix + strings.ToUpper(key[:1]) + key[1:] // f1 → F1
+	}
+	return prefix + key
+}
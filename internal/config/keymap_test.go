@@ -0,0 +1,91 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDefaultKeyMapIsValid(t *testing.T) {
+	if err := DefaultKeyMap().Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestKeysFromConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := filepath.Join(home, ".config", "endorse")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	toml := "[keys]\nreply = [\"ctrl+r\", \"space\"]\ndelete = []\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.toml"), []byte(toml), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Keys.Matches(ActionReply, "ctrl+r") || !cfg.Keys.Matches(ActionReply, " ") || cfg.Keys.Matches(ActionReply, "r") {
+		t.Errorf("expected reply rebound to ctrl+r and space, got %q", cfg.Keys[ActionReply])
+	}
+	if len(cfg.Keys[ActionDelete]) != 0 || cfg.Keys.Label(ActionDelete) != "" {
+		t.Errorf("expected delete unbound, got %q", cfg.Keys[ActionDelete])
+	}
+	if !cfg.Keys.Matches(ActionQuit, "q") {
+		t.Error("expected actions left out of the file to keep their defaults")
+	}
+}
+
+func TestKeyMapConflicts(t *testing.T) {
+	tests := []struct {
+		name string
+		bind map[string][]string
+		want string
+	}{
+		{"same panel", map[string][]string{ActionRetry: {"r"}}, `"r" is bound to both reply and retry in the thread`},
+		{"global and panel", map[string][]string{ActionPin: {"q"}}, `"q" is bound to both quit and pin`},
+		{"typed in compose", map[string][]string{ActionSend: {"s"}}, "can't be used while composing"},
+		{"unknown key", map[string][]string{ActionSend: {"ctrl+enter"}}, `unknown key "ctrl+enter"`},
+		{"unknown action", map[string][]string{"launch": {"L"}}, `unknown action "launch"`},
+		{"ctrl+c", map[string][]string{ActionBack: {"ctrl+c"}}, "always quits"},
+	}
+	for _, tt := range tests {
+		k := DefaultKeyMap()
+		for action, keys := range tt.bind {
+			k[action] = keys
+		}
+		if err := k.Validate(); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected an error containing %q, got %v", tt.name, tt.want, err)
+		}
+	}
+
+	// Actions in different panels may share a key
+	k := DefaultKeyMap()
+	k[ActionTop] = []string{"R"}
+	if err := k.Validate(); err != nil {
+		t.Errorf("expected top and retry to share R, got %v", err)
+	}
+}
+
+func TestKeyLabel(t *testing.T) {
+	for key, want := range map[string]string{
+		"j":         "j",
+		"G":         "G",
+		"ctrl+d":    "Ctrl+D",
+		"enter":     "Enter",
+		"shift+tab": "Shift+Tab",
+		"pgdown":    "PgDn",
+		"down":      "↓",
+		"alt+x":     "Alt+x",
+		"f5":        "F5",
+		" ":         "Space",
+	} {
+		if got := KeyLabel(key); got != want {
+			t.Errorf("KeyLabel(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
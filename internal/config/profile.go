@@ -0,0 +1,131 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// DefaultProfile is the profile used unless another is chosen. Its data lives
+// directly in the config directory, where it always has, so existing
+// installs keep working unchanged.
+const DefaultProfile = "default"
+
+// maxProfileName bounds profile names, which become directory names.
+const maxProfileName = 32
+
+var (
+	profileMu     sync.RWMutex // guards activeProfile
+	activeProfile = DefaultProfile
+
+	// switchMu is held while the profile changes and by writes made through
+	// InProfile, so neither can interleave with the other
+	switchMu sync.Mutex
+)
+
+// ValidateProfileName reports whether name can be used as a profile name:
+// letters, digits, "-" and "_", starting with a letter or digit.
+func ValidateProfileName(name string) error {
+	if name == "" {
+		return fmt.Errorf("profile name is empty")
+	}
+	if len(name) > maxProfileName {
+		return fmt.Errorf("profile name %q is longer than %d characters", name, maxProfileName)
+	}
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case (r == '-' || r == '_') && i > 0:
+		default:
+			return fmt.Errorf("profile name %q may only contain letters, digits, - and _", name)
+		}
+	}
+	return nil
+}
+
+// SetProfile makes name the active profile. Credentials, the message cache,
+// the outbox and folder state are read from and written to its directory.
+func SetProfile(name string) error {
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+	switchMu.Lock()
+	defer switchMu.Unlock()
+	profileMu.Lock()
+	activeProfile = name
+	profileMu.Unlock()
+	return nil
+}
+
+// ActiveProfile returns the name of the active profile.
+func ActiveProfile() string {
+	profileMu.RLock()
+	defer profileMu.RUnlock()
+	return activeProfile
+}
+
+// InProfile runs fn while name is still the active profile, so a write
+// queued before a switch can't land in another profile's directory. It does
+// nothing if a different profile has become active.
+func InProfile(name string, fn func() error) error {
+	switchMu.Lock()
+	defer switchMu.Unlock()
+	if ActiveProfile() != name {
+		return nil
+	}
+	return fn()
+}
+
+// ProfileDir returns the data directory for a profile.
+func ProfileDir(name string) (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	if name == DefaultProfile {
+		return dir, nil
+	}
+	return filepath.Join(dir, "profiles", name), nil
+}
+
+// DataDir returns the data directory for the active profile.
+func DataDir() (string, error) {
+	return ProfileDir(ActiveProfile())
+}
+
+// Profiles lists the default profile followed by every named profile that
+// has a directory, sorted by name.
+func Profiles() ([]string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, "profiles"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() && e.Name() != DefaultProfile && ValidateProfileName(e.Name()) == nil {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return append([]string{DefaultProfile}, names...), nil
+}
+
+// CreateProfile makes the directory for a new named profile.
+func CreateProfile(name string) error {
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+	dir, err := ProfileDir(name)
+	if err != nil {
+		return err
+	}
+	return os.MkdirAll(dir, 0700)
+}
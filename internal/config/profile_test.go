@@ -0,0 +1,102 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestValidateProfileName(t *testing.T) {
+	for _, name := range []string{"work", "acme-recruiting", "seat_2"} {
+		if err := ValidateProfileName(name); err != nil {
+			t.Errorf("ValidateProfileName(%q) = %v, want nil", name, err)
+		}
+	}
+	for _, name := range []string{"", "-work", "../work", "a/b", "has space"} {
+		if err := ValidateProfileName(name); err == nil {
+			t.Errorf("ValidateProfileName(%q) = nil, want an error", name)
+		}
+	}
+}
+
+func TestProfileDirs(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Cleanup(func() { _ = SetProfile(DefaultProfile) })
+	base := filepath.Join(home, ".config", "endorse")
+
+	if dir, _ := DataDir(); dir != base {
+		t.Errorf("default profile should use the config directory, got %s", dir)
+	}
+	if err := SetProfile("work"); err != nil {
+		t.Fatal(err)
+	}
+	if dir, _ := DataDir(); dir != filepath.Join(base, "profiles", "work") {
+		t.Errorf("unexpected data directory for work: %s", dir)
+	}
+	if path, _ := CredentialsPath(); path != filepath.Join(base, "profiles", "work", "credentials.json") {
+		t.Errorf("unexpected credentials path for work: %s", path)
+	}
+}
+
+func TestProfilesListsDirectories(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	for _, name := range []string{"zeta", "acme"} {
+		if err := CreateProfile(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Stray files and badly named directories aren't profiles
+	base := filepath.Join(home, ".config", "endorse", "profiles")
+	_ = os.WriteFile(filepath.Join(base, "notes.txt"), nil, 0600)
+	_ = os.Mkdir(filepath.Join(base, ".hidden"), 0700)
+
+	got, err := Profiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{DefaultProfile, "acme", "zeta"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Profiles() = %v, want %v", got, want)
+	}
+}
+
+func TestInProfileSkipsAfterSwitch(t *testing.T) {
+	t.Cleanup(func() { _ = SetProfile(DefaultProfile) })
+
+	ran := false
+	_ = InProfile(DefaultProfile, func() error { ran = true; return nil })
+	if !ran {
+		t.Error("expected fn to run while its profile is active")
+	}
+
+	_ = SetProfile("work")
+	ran = false
+	_ = InProfile(DefaultProfile, func() error { ran = true; return nil })
+	if ran {
+		t.Error("expected fn to be skipped once another profile is active")
+	}
+}
+
+func TestWriteFileLeavesNoTempFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "outbox.json")
+	for _, data := range []string{"first", "second"} {
+		if err := WriteFile(path, []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil || string(got) != "second" {
+		t.Errorf("expected the last write, got %q, %v", got, err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("expected a private file, got %v, %v", info.Mode(), err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("expected only the file itself, got %d entries", len(entries))
+	}
+}
@@ -1,6 +1,17 @@
 package config
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/charmbracelet/lipgloss"
+)
 
 // Theme defines a color palette for the application.
 type Theme struct {
@@ -58,15 +69,249 @@ var Dracula = Theme{
 	OwnSender:     lipgloss.Color("#8be9fd"),
 }
 
+// Nord is a cool, low-contrast dark theme.
+var Nord = Theme{
+	Name:          "nord",
+	Background:    lipgloss.Color("#2e3440"),
+	Foreground:    lipgloss.Color("#d8dee9"),
+	CurrentLine:   lipgloss.Color("#3b4252"),
+	Selection:     lipgloss.Color("#434c5e"),
+	Comment:       lipgloss.Color("#7b88a1"),
+	Subtle:        lipgloss.Color("#9aa5b8"),
+	Primary:       lipgloss.Color("#88c0d0"),
+	Secondary:     lipgloss.Color("#b48ead"),
+	Success:       lipgloss.Color("#a3be8c"),
+	Warning:       lipgloss.Color("#ebcb8b"),
+	Error:         lipgloss.Color("#bf616a"),
+	Info:          lipgloss.Color("#81a1c1"),
+	Accent:        lipgloss.Color("#88c0d0"),
+	AccentDim:     lipgloss.Color("#5e81ac"),
+	Border:        lipgloss.Color("#434c5e"),
+	BorderFocused: lipgloss.Color("#88c0d0"),
+	Unread:        lipgloss.Color("#a3be8c"),
+	OwnMessage:    lipgloss.Color("#434c5e"),
+	OtherMessage:  lipgloss.Color("#3b4252"),
+	OwnSender:     lipgloss.Color("#8fbcbb"),
+}
+
+// Gruvbox is a warm, retro dark theme.
+var Gruvbox = Theme{
+	Name:          "gruvbox",
+	Background:    lipgloss.Color("#282828"),
+	Foreground:    lipgloss.Color("#ebdbb2"),
+	CurrentLine:   lipgloss.Color("#3c3836"),
+	Selection:     lipgloss.Color("#504945"),
+	Comment:       lipgloss.Color("#a89984"),
+	Subtle:        lipgloss.Color("#bdae93"),
+	Primary:       lipgloss.Color("#fabd2f"),
+	Secondary:     lipgloss.Color("#d3869b"),
+	Success:       lipgloss.Color("#b8bb26"),
+	Warning:       lipgloss.Color("#fabd2f"),
+	Error:         lipgloss.Color("#fb4934"),
+	Info:          lipgloss.Color("#83a598"),
+	Accent:        lipgloss.Color("#fe8019"),
+	AccentDim:     lipgloss.Color("#d65d0e"),
+	Border:        lipgloss.Color("#504945"),
+	BorderFocused: lipgloss.Color("#fe8019"),
+	Unread:        lipgloss.Color("#b8bb26"),
+	OwnMessage:    lipgloss.Color("#504945"),
+	OtherMessage:  lipgloss.Color("#3c3836"),
+	OwnSender:     lipgloss.Color("#8ec07c"),
+}
+
+// SolarizedLight is a light theme on Solarized's cream background.
+var SolarizedLight = Theme{
+	Name:          "solarized-light",
+	Background:    lipgloss.Color("#fdf6e3"),
+	Foreground:    lipgloss.Color("#586e75"),
+	CurrentLine:   lipgloss.Color("#eee8d5"),
+	Selection:     lipgloss.Color("#eee8d5"),
+	Comment:       lipgloss.Color("#839496"),
+	Subtle:        lipgloss.Color("#93a1a1"),
+	Primary:       lipgloss.Color("#268bd2"),
+	Secondary:     lipgloss.Color("#d33682"),
+	Success:       lipgloss.Color("#859900"),
+	Warning:       lipgloss.Color("#b58900"),
+	Error:         lipgloss.Color("#dc322f"),
+	Info:          lipgloss.Color("#2aa198"),
+	Accent:        lipgloss.Color("#268bd2"),
+	AccentDim:     lipgloss.Color("#93a1a1"),
+	Border:        lipgloss.Color("#ddd6c1"),
+	BorderFocused: lipgloss.Color("#268bd2"),
+	Unread:        lipgloss.Color("#859900"),
+	OwnMessage:    lipgloss.Color("#eee8d5"),
+	OtherMessage:  lipgloss.Color("#f5efdc"),
+	OwnSender:     lipgloss.Color("#2aa198"),
+}
+
+// GitHubLight is a light theme for white terminal backgrounds.
+var GitHubLight = Theme{
+	Name:          "github-light",
+	Background:    lipgloss.Color("#ffffff"),
+	Foreground:    lipgloss.Color("#1f2328"),
+	CurrentLine:   lipgloss.Color("#f6f8fa"),
+	Selection:     lipgloss.Color("#ddf4ff"),
+	Comment:       lipgloss.Color("#59636e"),
+	Subtle:        lipgloss.Color("#6e7781"),
+	Primary:       lipgloss.Color("#0969da"),
+	Secondary:     lipgloss.Color("#8250df"),
+	Success:       lipgloss.Color("#1a7f37"),
+	Warning:       lipgloss.Color("#9a6700"),
+	Error:         lipgloss.Color("#d1242f"),
+	Info:          lipgloss.Color("#1b7c83"),
+	Accent:        lipgloss.Color("#0969da"),
+	AccentDim:     lipgloss.Color("#54aeff"),
+	Border:        lipgloss.Color("#d0d7de"),
+	BorderFocused: lipgloss.Color("#0969da"),
+	Unread:        lipgloss.Color("#1a7f37"),
+	OwnMessage:    lipgloss.Color("#ddf4ff"),
+	OtherMessage:  lipgloss.Color("#f6f8fa"),
+	OwnSender:     lipgloss.Color("#0550ae"),
+}
+
+// builtinThemes are the themes that ship with endorse, in the order they
+// are cycled through.
+var builtinThemes = []Theme{Dracula, Nord, Gruvbox, SolarizedLight, GitHubLight}
+
 // DefaultTheme returns the default application theme.
 func DefaultTheme() Theme {
 	return Dracula
 }
 
-// ThemeByName returns a theme by name, falling back to Dracula.
+// ThemeByName returns a theme by name, falling back to Dracula if it doesn't
+// exist or its file is invalid.
 func ThemeByName(name string) Theme {
-	switch name {
-	default:
+	theme, err := LoadTheme(name)
+	if err != nil {
 		return Dracula
 	}
+	return theme
+}
+
+// ThemesDir returns the directory holding theme files. Themes are shared by
+// all profiles.
+func ThemesDir() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "themes"), nil
+}
+
+// LoadTheme returns the named theme: a file <name>.toml in ThemesDir, which
+// takes precedence, or a built-in theme. An empty name is the default.
+func LoadTheme(name string) (Theme, error) {
+	if name == "" {
+		return DefaultTheme(), nil
+	}
+	if strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return Theme{}, fmt.Errorf("invalid theme name %q", name)
+	}
+
+	dir, err := ThemesDir()
+	if err == nil {
+		data, err := os.ReadFile(filepath.Join(dir, name+".toml"))
+		if err == nil {
+			return parseTheme(name, data)
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return Theme{}, err
+		}
+	}
+
+	for _, t := range builtinThemes {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+	return Theme{}, fmt.Errorf("unknown theme %q", name)
+}
+
+// Themes lists the built-in themes followed by the theme files in
+// ThemesDir, sorted. A file named after a built-in theme replaces it.
+func Themes() ([]string, error) {
+	var names []string
+	for _, t := range builtinThemes {
+		names = append(names, t.Name)
+	}
+
+	dir, err := ThemesDir()
+	if err != nil {
+		return names, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return names, nil
+		}
+		return names, err
+	}
+	var custom []string
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".toml")
+		if !ok || e.IsDir() || strings.HasPrefix(name, ".") || slices.Contains(names, name) {
+			continue
+		}
+		custom = append(custom, name)
+	}
+	slices.Sort(custom)
+	return append(names, custom...), nil
+}
+
+// themeKeys maps the keys of a theme file to the colors they set.
+var themeKeys = map[string]func(*Theme) *lipgloss.Color{
+	"background":     func(t *Theme) *lipgloss.Color { return &t.Background },
+	"foreground":     func(t *Theme) *lipgloss.Color { return &t.Foreground },
+	"current_line":   func(t *Theme) *lipgloss.Color { return &t.CurrentLine },
+	"selection":      func(t *Theme) *lipgloss.Color { return &t.Selection },
+	"comment":        func(t *Theme) *lipgloss.Color { return &t.Comment },
+	"subtle":         func(t *Theme) *lipgloss.Color { return &t.Subtle },
+	"primary":        func(t *Theme) *lipgloss.Color { return &t.Primary },
+	"secondary":      func(t *Theme) *lipgloss.Color { return &t.Secondary },
+	"success":        func(t *Theme) *lipgloss.Color { return &t.Success },
+	"warning":        func(t *Theme) *lipgloss.Color { return &t.Warning },
+	"error":          func(t *Theme) *lipgloss.Color { return &t.Error },
+	"info":           func(t *Theme) *lipgloss.Color { return &t.Info },
+	"accent":         func(t *Theme) *lipgloss.Color { return &t.Accent },
+	"accent_dim":     func(t *Theme) *lipgloss.Color { return &t.AccentDim },
+	"border":         func(t *Theme) *lipgloss.Color { return &t.Border },
+	"border_focused": func(t *Theme) *lipgloss.Color { return &t.BorderFocused },
+	"unread":         func(t *Theme) *lipgloss.Color { return &t.Unread },
+	"own_message":    func(t *Theme) *lipgloss.Color { return &t.OwnMessage },
+	"other_message":  func(t *Theme) *lipgloss.Color { return &t.OtherMessage },
+	"own_sender":     func(t *Theme) *lipgloss.Color { return &t.OwnSender },
+}
+
+var hexColor = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// parseTheme reads a theme file. Colors it leaves out come from the
+// built-in theme named by base, Dracula if unset.
+func parseTheme(name string, data []byte) (Theme, error) {
+	var values map[string]string
+	if err := toml.Unmarshal(data, &values); err != nil {
+		return Theme{}, fmt.Errorf("theme %s: %w", name, err)
+	}
+
+	theme := DefaultTheme()
+	if base, ok := values["base"]; ok {
+		i := slices.IndexFunc(builtinThemes, func(t Theme) bool { return t.Name == base })
+		if i < 0 {
+			return Theme{}, fmt.Errorf("theme %s: unknown base theme %q", name, base)
+		}
+		theme = builtinThemes[i]
+		delete(values, "base")
+	}
+	theme.Name = name
+
+	for key, value := range values {
+		field, ok := themeKeys[key]
+		if !ok {
+			return Theme{}, fmt.Errorf("theme %s: unknown key %q", name, key)
+		}
+		if !hexColor.MatchString(value) {
+			return Theme{}, fmt.Errorf("theme %s: %s = %q is not a hex color (#rgb or #rrggbb)", name, key, value)
+		}
+		*field(&theme) = lipgloss.Color(value)
+	}
+	return theme, nil
 }
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeTheme(t *testing.T, home, name, content string) {
+	t.Helper()
+	dir := filepath.Join(home, ".config", "endorse", "themes")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name+".toml"), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadBuiltinThemes(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	for _, builtin := range builtinThemes {
+		theme, err := LoadTheme(builtin.Name)
+		if err != nil || theme != builtin {
+			t.Errorf("LoadTheme(%q) = %q, %v", builtin.Name, theme.Name, err)
+		}
+	}
+	if theme, err := LoadTheme(""); err != nil || theme != Dracula {
+		t.Errorf("expected the default theme for an empty name, got %q, %v", theme.Name, err)
+	}
+	if _, err := LoadTheme("no-such-theme"); err == nil {
+		t.Error("expected an error for an unknown theme")
+	}
+	if _, err := LoadTheme("../config"); err == nil {
+		t.Error("expected an error for a path in the theme name")
+	}
+	if got := ThemeByName("no-such-theme"); got != Dracula {
+		t.Errorf("expected ThemeByName to fall back to Dracula, got %q", got.Name)
+	}
+}
+
+func TestLoadThemeFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTheme(t, home, "paper", "base = \"github-light\"\naccent = \"#c0ffee\"\nborder = \"#abc\"\n")
+
+	theme, err := LoadTheme("paper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if theme.Name != "paper" || theme.Accent != "#c0ffee" || theme.Border != "#abc" {
+		t.Errorf("expected the file's colors, got %+v", theme)
+	}
+	if theme.Background != GitHubLight.Background {
+		t.Errorf("expected colors left out to come from the base theme, got %s", theme.Background)
+	}
+
+	// A file named after a built-in theme replaces it
+	writeTheme(t, home, "nord", "accent = \"#000000\"\n")
+	if theme, _ := LoadTheme("nord"); theme.Accent != "#000000" || theme.Background != Dracula.Background {
+		t.Errorf("expected the nord file to win over the built-in, got %+v", theme)
+	}
+
+	names, err := Themes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"dracula", "nord", "gruvbox", "solarized-light", "github-light", "paper"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("Themes() = %v, want %v", names, want)
+	}
+}
+
+func TestInvalidThemeFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{"accent = \"purple\"\n", "not a hex color"},
+		{"accent = \"#12345\"\n", "not a hex color"},
+		{"acent = \"#123456\"\n", "unknown key"},
+		{"base = \"vaporwave\"\n", "unknown base theme"},
+		{"accent = 42\n", "theme broken"},
+	}
+	for _, tt := range tests {
+		writeTheme(t, home, "broken", tt.content)
+		if _, err := LoadTheme("broken"); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%q: expected an error containing %q, got %v", tt.content, tt.want, err)
+		}
+	}
+}
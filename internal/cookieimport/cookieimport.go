@@ -0,0 +1,188 @@
+// Package cookieimport reads a LinkedIn session out of browser exports:
+// Netscape cookies.txt files and HAR captures from the network tab.
+package cookieimport
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Session is what endorse needs from an export to sign in.
+type Session struct {
+	Cookie       string // Cookie header with li_at and, if present, JSESSIONID
+	PageInstance string // x-li-page-instance header, from HAR captures only
+	XLiTrack     string // x-li-track header, from HAR captures only
+}
+
+// ErrNoSession means the export has no li_at cookie for linkedin.com.
+var ErrNoSession = errors.New("no li_at cookie for linkedin.com found")
+
+// ReadFile reads a cookies.txt or HAR file.
+func ReadFile(path string) (Session, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Session{}, err
+	}
+	return Parse(data)
+}
+
+// Parse reads a session from a cookies.txt file or a HAR capture, telling
+// them apart by content: HAR files are JSON.
+func Parse(data []byte) (Session, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		return parseHAR(trimmed)
+	}
+	return parseCookiesTxt(data, time.Now())
+}
+
+// cookies collects the two cookies endorse uses.
+type cookies struct {
+	liAt       string
+	jsessionID string
+}
+
+func (c *cookies) set(name, value string) {
+	switch name {
+	case "li_at":
+		c.liAt = value
+	case "JSESSIONID":
+		c.jsessionID = value
+	}
+}
+
+func (c cookies) header() string {
+	h := "li_at=" + c.liAt
+	if c.jsessionID != "" {
+		h += "; JSESSIONID=" + c.jsessionID
+	}
+	return h
+}
+
+// isLinkedIn reports whether a cookie domain or URL host is linkedin.com or
+// one of its subdomains.
+func isLinkedIn(host string) bool {
+	host = strings.ToLower(strings.TrimPrefix(host, "."))
+	return host == "linkedin.com" || strings.HasSuffix(host, ".linkedin.com")
+}
+
+// parseCookiesTxt reads the Netscape format written by browser extensions
+// and curl: one tab-separated cookie per line, with "#HttpOnly_" before the
+// domain of HttpOnly cookies such as li_at.
+func parseCookiesTxt(data []byte, now time.Time) (Session, error) {
+	var found cookies
+	var expired time.Time
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if rest, ok := strings.CutPrefix(line, "#HttpOnly_"); ok {
+			line = rest
+		} else if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) != 7 || !isLinkedIn(fields[0]) {
+			continue
+		}
+		name, value := fields[5], fields[6]
+		if exp, err := strconv.ParseInt(fields[4], 10, 64); err == nil && exp > 0 {
+			// Zero means a session cookie, which never expires on disk
+			if at := time.Unix(exp, 0); at.Before(now) {
+				if name == "li_at" {
+					expired = at
+				}
+				continue
+			}
+		}
+		found.set(name, value)
+	}
+
+	if found.liAt == "" {
+		if !expired.IsZero() {
+			return Session{}, fmt.Errorf("the li_at cookie expired on %s; sign in to LinkedIn and export again", expired.Format("2 Jan 2006"))
+		}
+		return Session{}, ErrNoSession
+	}
+	return Session{Cookie: found.header()}, nil
+}
+
+// har is the part of the HAR 1.2 format endorse reads.
+type har struct {
+	Log struct {
+		Entries []struct {
+			Request struct {
+				URL     string      `json:"url"`
+				Headers []harHeader `json:"headers"`
+				Cookies []harHeader `json:"cookies"`
+			} `json:"request"`
+			Response struct {
+				Cookies []harHeader `json:"cookies"`
+			} `json:"response"`
+		} `json:"entries"`
+	} `json:"log"`
+}
+
+type harHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// parseHAR reads cookies and LinkedIn's client headers from requests to
+// linkedin.com. Entries are in time order, so later values win, including
+// cookies the server replaced in a response.
+func parseHAR(data []byte) (Session, error) {
+	var h har
+	if err := json.Unmarshal(data, &h); err != nil {
+		return Session{}, fmt.Errorf("reading HAR file: %w", err)
+	}
+
+	var found cookies
+	var s Session
+	requests := 0
+	for _, e := range h.Log.Entries {
+		u, err := url.Parse(e.Request.URL)
+		if err != nil || !isLinkedIn(u.Hostname()) {
+			continue
+		}
+		requests++
+
+		for _, c := range e.Request.Cookies {
+			found.set(c.Name, c.Value)
+		}
+		for _, hdr := range e.Request.Headers {
+			switch strings.ToLower(hdr.Name) {
+			case "cookie":
+				// Some browsers leave the cookies array empty
+				if parsed, err := http.ParseCookie(hdr.Value); err == nil {
+					for _, c := range parsed {
+						found.set(c.Name, c.Value)
+					}
+				}
+			case "x-li-track":
+				s.XLiTrack = hdr.Value
+			case "x-li-page-instance":
+				s.PageInstance = hdr.Value
+			}
+		}
+		for _, c := range e.Response.Cookies {
+			found.set(c.Name, c.Value)
+		}
+	}
+
+	if found.liAt == "" {
+		if requests > 0 {
+			// Chrome and Firefox strip cookies from HAR exports by default
+			return Session{}, fmt.Errorf("%w; the HAR file has requests to LinkedIn but no cookies, so export it again with sensitive data included", ErrNoSession)
+		}
+		return Session{}, ErrNoSession
+	}
+	s.Cookie = found.header()
+	return s, nil
+}
@@ -0,0 +1,74 @@
+package cookieimport
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var now = time.Date(2025, time.March, 4, 10, 0, 0, 0, time.UTC)
+
+func TestParseCookiesTxt(t *testing.T) {
+	data := strings.Join([]string{
+		"# Netscape HTTP Cookie File",
+		"",
+		"#HttpOnly_.www.linkedin.com\tTRUE\t/\tTRUE\t1900000000\tli_at\tAQEDtoken",
+		".www.linkedin.com\tTRUE\t/\tTRUE\t0\tJSESSIONID\t\"ajax:123\"",
+		".example.com\tTRUE\t/\tFALSE\t1900000000\tli_at\tnot-linkedin",
+		".linkedin.com\tTRUE\t/\tFALSE\t1900000000\tlang\tv=2&lang=en-us",
+	}, "\r\n")
+
+	s, err := parseCookiesTxt([]byte(data), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `li_at=AQEDtoken; JSESSIONID="ajax:123"`; s.Cookie != want {
+		t.Errorf("Cookie = %q, want %q", s.Cookie, want)
+	}
+}
+
+func TestParseCookiesTxtExpired(t *testing.T) {
+	data := "#HttpOnly_.linkedin.com\tTRUE\t/\tTRUE\t1700000000\tli_at\told\n"
+	_, err := parseCookiesTxt([]byte(data), now)
+	if err == nil || !strings.Contains(err.Error(), "expired on 14 Nov 2023") {
+		t.Errorf("expected an expiry error, got %v", err)
+	}
+}
+
+func TestParseHAR(t *testing.T) {
+	data := `{"log": {"entries": [
+		{"request": {"url": "https://www.linkedin.com/voyager/api/me",
+			"headers": [{"name": "cookie", "value": "li_at=first; JSESSIONID=\"ajax:1\""}],
+			"cookies": []},
+		 "response": {"cookies": []}},
+		{"request": {"url": "https://static.example.com/x.js",
+			"headers": [{"name": "x-li-track", "value": "wrong"}], "cookies": []},
+		 "response": {"cookies": []}},
+		{"request": {"url": "https://www.linkedin.com/voyager/api/messaging",
+			"headers": [
+				{"name": "X-Li-Track", "value": "{\"clientVersion\":\"1.13\"}"},
+				{"name": "x-li-page-instance", "value": "urn:li:page:d_flagship3_messaging;abc"}],
+			"cookies": [{"name": "li_at", "value": "second"}]},
+		 "response": {"cookies": []}}
+	]}}`
+
+	s, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Cookie != "li_at=second; JSESSIONID=ajax:1" {
+		t.Errorf("expected the latest li_at with JSESSIONID, got %q", s.Cookie)
+	}
+	if s.XLiTrack != `{"clientVersion":"1.13"}` || s.PageInstance != "urn:li:page:d_flagship3_messaging;abc" {
+		t.Errorf("unexpected headers: %+v", s)
+	}
+}
+
+func TestParseHARWithoutCookies(t *testing.T) {
+	data := `{"log": {"entries": [{"request": {"url": "https://www.linkedin.com/feed/", "headers": [], "cookies": []}, "response": {"cookies": []}}]}}`
+	_, err := Parse([]byte(data))
+	if !errors.Is(err, ErrNoSession) || !strings.Contains(err.Error(), "sensitive data") {
+		t.Errorf("expected a hint about stripped cookies, got %v", err)
+	}
+}
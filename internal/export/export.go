@@ -0,0 +1,414 @@
+// Package export writes conversation transcripts to Markdown, JSON or mbox
+// files for archiving.
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.mau.fi/mautrix-linkedin/pkg/linkedingo"
+
+	"github.com/ggfevans/endorse/internal/linkedin"
+	"github.com/ggfevans/endorse/internal/util"
+)
+
+// pageSize is how many messages are requested per history page.
+const pageSize = 50
+
+// Format is an export file format.
+type Format int
+
+const (
+	// Markdown writes a readable transcript per conversation.
+	Markdown Format = iota
+	// JSON writes the conversations and messages as structured data.
+	JSON
+	// Mbox writes one email per message in RFC 4155 mbox format.
+	Mbox
+)
+
+// ParseFormat parses a format name: "markdown" (or "md"), "json" or "mbox".
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "markdown", "md":
+		return Markdown, nil
+	case "json":
+		return JSON, nil
+	case "mbox":
+		return Mbox, nil
+	}
+	return 0, fmt.Errorf("unknown export format %q (want markdown, json or mbox)", name)
+}
+
+// Ext returns the file extension for the format, including the dot.
+func (f Format) Ext() string {
+	switch f {
+	case JSON:
+		return ".json"
+	case Mbox:
+		return ".mbox"
+	default:
+		return ".md"
+	}
+}
+
+// Transcript is a conversation with its messages, oldest first.
+type Transcript struct {
+	Conversation linkedin.DisplayConversation
+	Messages     []linkedin.DisplayMessage
+}
+
+// Filter narrows an export. Zero values don't filter.
+type Filter struct {
+	Since       time.Time // keep messages at or after this time
+	Until       time.Time // keep messages before this time
+	Participant string    // keep conversations with a participant whose name contains this
+}
+
+// Includes reports whether a conversation passes the participant filter and
+// may have messages in the date range.
+func (f Filter) Includes(c linkedin.DisplayConversation) bool {
+	if !f.Since.IsZero() && c.LastActivityAt.Before(f.Since) {
+		return false
+	}
+	if f.Participant == "" {
+		return true
+	}
+	q := strings.ToLower(f.Participant)
+	for _, p := range c.Participants {
+		if !p.IsOwnUser && strings.Contains(strings.ToLower(p.Name), q) {
+			return true
+		}
+	}
+	return false
+}
+
+// InRange reports whether a message falls in the date range.
+func (f Filter) InRange(t time.Time) bool {
+	if !f.Since.IsZero() && t.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !t.Before(f.Until) {
+		return false
+	}
+	return true
+}
+
+// History pages back through a conversation until it reaches the start or
+// messages older than since, returning the messages oldest first.
+func History(client *linkedin.Sync, urn linkedingo.URN, since time.Time) ([]linkedin.DisplayMessage, error) {
+	msgs, cursor, err := client.Messages(urn, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(msgs))
+	for _, m := range msgs {
+		seen[m.ID] = true
+	}
+	for cursor != "" && !reachedBefore(msgs, since) {
+		var page []linkedin.DisplayMessage
+		page, cursor, err = client.MessagesBefore(urn, cursor, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		added := 0
+		for _, m := range page {
+			if !seen[m.ID] {
+				seen[m.ID] = true
+				msgs = append(msgs, m)
+				added++
+			}
+		}
+		if added == 0 {
+			break // the server is repeating itself
+		}
+	}
+
+	sort.SliceStable(msgs, func(i, j int) bool { return msgs[i].Timestamp.Before(msgs[j].Timestamp) })
+	return msgs, nil
+}
+
+// reachedBefore reports whether any message is older than since.
+func reachedBefore(msgs []linkedin.DisplayMessage, since time.Time) bool {
+	if since.IsZero() {
+		return false
+	}
+	for _, m := range msgs {
+		if m.Timestamp.Before(since) {
+			return true
+		}
+	}
+	return false
+}
+
+// Collect fetches the full history of each conversation that passes the
+// filter, keeping the messages in its date range. Conversations left with
+// no messages are dropped.
+func Collect(client *linkedin.Sync, convs []linkedin.DisplayConversation, f Filter) ([]Transcript, error) {
+	var out []Transcript
+	for _, c := range convs {
+		if !f.Includes(c) {
+			continue
+		}
+		msgs, err := History(client, c.URN, f.Since)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", c.Title, err)
+		}
+		var kept []linkedin.DisplayMessage
+		for _, m := range msgs {
+			if f.InRange(m.Timestamp) {
+				kept = append(kept, m)
+			}
+		}
+		if len(kept) > 0 {
+			out = append(out, Transcript{Conversation: c, Messages: kept})
+		}
+	}
+	return out, nil
+}
+
+// Write writes transcripts in the given format.
+func Write(w io.Writer, f Format, transcripts []Transcript) error {
+	switch f {
+	case JSON:
+		return writeJSON(w, transcripts)
+	case Mbox:
+		return writeMbox(w, transcripts)
+	default:
+		return writeMarkdown(w, transcripts)
+	}
+}
+
+// WriteFile writes transcripts to a file, creating its directory. Exports
+// hold private messages, so both are readable by the user only.
+func WriteFile(path string, f Format, transcripts []Transcript) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if err := Write(file, f, transcripts); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// FileName returns a file name for exporting a conversation on a given day,
+// like "karl-havoc-2025-03-03.md".
+func FileName(title string, day time.Time, f Format) string {
+	slug := strings.Trim(strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return '-'
+	}, title), "-")
+	for strings.Contains(slug, "--") {
+		slug = strings.ReplaceAll(slug, "--", "-")
+	}
+	if slug == "" {
+		slug = "conversation"
+	}
+	return slug + "-" + day.Format("2006-01-02") + f.Ext()
+}
+
+// The JSON export schema. It is kept apart from the display types so the
+// file format stays stable as they change, and uses the same snake_case
+// names as the subcommands' --json output.
+type transcriptJSON struct {
+	Conversation conversationJSON `json:"conversation"`
+	Messages     []messageJSON    `json:"messages"`
+}
+
+type conversationJSON struct {
+	ID             string            `json:"id"`
+	Title          string            `json:"title"`
+	Participants   []participantJSON `json:"participants"`
+	LastActivityAt time.Time         `json:"last_activity_at"`
+	Archived       bool              `json:"archived"`
+}
+
+type participantJSON struct {
+	Name     string `json:"name"`
+	Headline string `json:"headline,omitempty"`
+	URN      string `json:"urn,omitempty"`
+	IsOwn    bool   `json:"is_own"`
+}
+
+type messageJSON struct {
+	ID        string    `json:"id"`
+	Sender    string    `json:"sender"`
+	SenderURN string    `json:"sender_urn,omitempty"`
+	Body      string    `json:"body"`
+	Timestamp time.Time `json:"timestamp"`
+	IsOwn     bool      `json:"is_own"`
+}
+
+func toTranscriptJSON(t Transcript) transcriptJSON {
+	c := t.Conversation
+	out := transcriptJSON{
+		Conversation: conversationJSON{
+			ID:             c.ID,
+			Title:          c.Title,
+			Participants:   []participantJSON{},
+			LastActivityAt: c.LastActivityAt,
+			Archived:       c.Archived,
+		},
+		Messages: []messageJSON{},
+	}
+	for _, p := range c.Participants {
+		out.Conversation.Participants = append(out.Conversation.Participants, participantJSON{
+			Name:     p.Name,
+			Headline: p.Headline,
+			URN:      urnString(p.URN),
+			IsOwn:    p.IsOwnUser,
+		})
+	}
+	for _, m := range t.Messages {
+		out.Messages = append(out.Messages, messageJSON{
+			ID:        m.ID,
+			Sender:    m.Sender,
+			SenderURN: urnString(m.SenderURN),
+			Body:      m.Body,
+			Timestamp: m.Timestamp,
+			IsOwn:     m.IsOwn,
+		})
+	}
+	return out
+}
+
+// urnString returns the URN as a string, or "" if it is unset.
+func urnString(u linkedingo.URN) string {
+	if u.IsEmpty() {
+		return ""
+	}
+	return u.String()
+}
+
+func writeJSON(w io.Writer, transcripts []Transcript) error {
+	out := make([]transcriptJSON, 0, len(transcripts))
+	for _, t := range transcripts {
+		out = append(out, toTranscriptJSON(t))
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+func writeMarkdown(w io.Writer, transcripts []Transcript) error {
+	var b strings.Builder
+	for i, t := range transcripts {
+		if i > 0 {
+			b.WriteString("\n---\n\n")
+		}
+		fmt.Fprintf(&b, "# %s\n\n", t.Conversation.Title)
+		if names := participantNames(t.Conversation); len(names) > 0 {
+			fmt.Fprintf(&b, "Participants: %s\n\n", strings.Join(names, ", "))
+		}
+
+		var day time.Time
+		for _, m := range t.Messages {
+			if day.IsZero() || !util.SameDay(day, m.Timestamp) {
+				day = m.Timestamp
+				fmt.Fprintf(&b, "## %s\n\n", m.Timestamp.Format("Mon 2 Jan 2006"))
+			}
+			fmt.Fprintf(&b, "**%s** · %s\n\n", m.Sender, m.Timestamp.Format("15:04"))
+			// Quote each line so message text can't become Markdown structure
+			for _, line := range strings.Split(m.Body, "\n") {
+				b.WriteString(strings.TrimRight("> "+line, " ") + "\n")
+			}
+			b.WriteString("\n")
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMbox(w io.Writer, transcripts []Transcript) error {
+	var b strings.Builder
+	for _, t := range transcripts {
+		to := make([]string, 0, len(t.Conversation.Participants))
+		for _, p := range t.Conversation.Participants {
+			to = append(to, address(p.Name, p.URN))
+		}
+		for _, m := range t.Messages {
+			from := address(m.Sender, m.SenderURN)
+			addr := mailbox(m.SenderURN)
+			ts := m.Timestamp.UTC()
+
+			fmt.Fprintf(&b, "From %s %s\n", addr, ts.Format(time.ANSIC))
+			fmt.Fprintf(&b, "From: %s\n", from)
+			if len(to) > 0 {
+				fmt.Fprintf(&b, "To: %s\n", strings.Join(to, ", "))
+			}
+			fmt.Fprintf(&b, "Date: %s\n", m.Timestamp.Format(time.RFC1123Z))
+			fmt.Fprintf(&b, "Subject: %s\n", mime.QEncoding.Encode("utf-8", t.Conversation.Title))
+			fmt.Fprintf(&b, "Message-ID: <%s>\n", messageID(m.ID))
+			b.WriteString("MIME-Version: 1.0\n")
+			b.WriteString("Content-Type: text/plain; charset=utf-8\n")
+			b.WriteString("Content-Transfer-Encoding: 8bit\n\n")
+			for _, line := range strings.Split(m.Body, "\n") {
+				b.WriteString(escapeFrom(line) + "\n")
+			}
+			b.WriteString("\n")
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// escapeFrom quotes body lines that would otherwise start a new message,
+// mboxrd style: "From " and any ">From " gain another ">".
+func escapeFrom(line string) string {
+	if strings.HasPrefix(strings.TrimLeft(line, ">"), "From ") {
+		return ">" + line
+	}
+	return line
+}
+
+// mailbox makes a placeholder address from a profile URN. The .invalid
+// domain is reserved, so it never reaches a real inbox.
+func mailbox(urn linkedingo.URN) string {
+	id := urn.ID()
+	if id == "" {
+		id = "unknown"
+	}
+	return strings.Map(func(r rune) rune {
+		if r == '@' || r == ' ' || r == '<' || r == '>' || r == ',' {
+			return '-'
+		}
+		return r
+	}, id) + "@linkedin.invalid"
+}
+
+// address formats a named address header value.
+func address(name string, urn linkedingo.URN) string {
+	return (&mail.Address{Name: name, Address: mailbox(urn)}).String()
+}
+
+// messageID turns a message URN into a Message-ID local part.
+func messageID(id string) string {
+	return strings.NewReplacer("<", "", ">", "", " ", "", "@", "-").Replace(id) + "@linkedin.invalid"
+}
+
+func participantNames(c linkedin.DisplayConversation) []string {
+	var names []string
+	for _, p := range c.Participants {
+		if !p.IsOwnUser && p.Name != "" {
+			names = append(names, p.Name)
+		}
+	}
+	return names
+}
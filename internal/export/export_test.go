@@ -0,0 +1,226 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"go.mau.fi/mautrix-linkedin/pkg/linkedingo"
+
+	"github.com/ggfevans/endorse/internal/linkedin"
+)
+
+var (
+	karlURN = linkedingo.NewURN("urn:li:member:karl")
+	ownURN  = linkedingo.NewURN("urn:li:member:me")
+	convURN = linkedingo.NewURN("urn:li:conversation:conv-karl")
+	day     = time.Date(2025, time.March, 3, 9, 0, 0, 0, time.UTC)
+)
+
+// pagedClient serves a conversation's history three messages at a time,
+// newest page first, with the page index as the cursor.
+type pagedClient struct {
+	*linkedin.DemoClient
+	history []linkedin.DisplayMessage
+	calls   int
+}
+
+func (c *pagedClient) page(n int) tea.Msg {
+	c.calls++
+	end := len(c.history) - n*3
+	start := max(end-3, 0)
+	cursor := ""
+	if start > 0 {
+		cursor = fmt.Sprint(n + 1)
+	}
+	return linkedin.MessagesLoadedMsg{Messages: c.history[start:end], PrevCursor: cursor}
+}
+
+func (c *pagedClient) FetchMessages(linkedingo.URN, time.Time, int) tea.Cmd {
+	return func() tea.Msg { return c.page(0) }
+}
+
+func (c *pagedClient) FetchMessagesWithCursor(_ linkedingo.URN, cursor string, _ int) tea.Cmd {
+	var n int
+	fmt.Sscan(cursor, &n)
+	return func() tea.Msg { return c.page(n) }
+}
+
+func newPagedClient(n int) *pagedClient {
+	c := &pagedClient{DemoClient: linkedin.NewDemoClient()}
+	for i := range n {
+		c.history = append(c.history, linkedin.DisplayMessage{
+			ID:        fmt.Sprintf("urn:li:msg:%d", i),
+			Sender:    "Karl Havoc",
+			SenderURN: karlURN,
+			Body:      fmt.Sprintf("message %d", i),
+			Timestamp: day.Add(time.Duration(i) * 12 * time.Hour),
+		})
+	}
+	return c
+}
+
+func sampleConversation() linkedin.DisplayConversation {
+	return linkedin.DisplayConversation{
+		ID:             convURN.String(),
+		Title:          "Karl Havoc",
+		URN:            convURN,
+		LastActivityAt: day.Add(72 * time.Hour),
+		Participants: []linkedin.DisplayParticipant{
+			{Name: "Karl Havoc", URN: karlURN},
+			{Name: "You", URN: ownURN, IsOwnUser: true},
+		},
+	}
+}
+
+func TestHistoryPagesToTheStart(t *testing.T) {
+	c := newPagedClient(8)
+	msgs, err := History(linkedin.NewSync(c), convURN, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 8 || c.calls != 3 {
+		t.Fatalf("expected 8 messages over 3 pages, got %d over %d", len(msgs), c.calls)
+	}
+	if msgs[0].Body != "message 0" || msgs[7].Body != "message 7" {
+		t.Errorf("expected messages oldest first, got %q … %q", msgs[0].Body, msgs[7].Body)
+	}
+}
+
+func TestHistoryStopsAtSince(t *testing.T) {
+	c := newPagedClient(8)
+	// Messages 5-7 are on the first page; message 4 is on the second
+	if _, err := History(linkedin.NewSync(c), convURN, day.Add(60*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if c.calls != 2 {
+		t.Errorf("expected paging to stop once past since, got %d pages", c.calls)
+	}
+}
+
+func TestCollectFilters(t *testing.T) {
+	client := linkedin.NewSync(newPagedClient(8))
+	conv := sampleConversation()
+
+	got, err := Collect(client, []linkedin.DisplayConversation{conv}, Filter{Since: day.Add(24 * time.Hour), Until: day.Add(48 * time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || len(got[0].Messages) != 2 {
+		t.Fatalf("expected messages 2 and 3 in range, got %+v", got)
+	}
+
+	got, _ = Collect(client, []linkedin.DisplayConversation{conv}, Filter{Participant: "howie"})
+	if len(got) != 0 {
+		t.Errorf("expected the participant filter to drop the conversation, got %d", len(got))
+	}
+	got, _ = Collect(client, []linkedin.DisplayConversation{conv}, Filter{Participant: "karl"})
+	if len(got) != 1 {
+		t.Errorf("expected a case-insensitive participant match, got %d", len(got))
+	}
+}
+
+func sampleTranscript() []Transcript {
+	return []Transcript{{
+		Conversation: sampleConversation(),
+		Messages: []linkedin.DisplayMessage{
+			{ID: "urn:li:msg:1", Sender: "Karl Havoc", SenderURN: karlURN, Body: "From the top\n# not a heading", Timestamp: day},
+			{ID: "urn:li:msg:2", Sender: "You", SenderURN: ownURN, Body: "Sure", Timestamp: day.Add(25 * time.Hour), IsOwn: true},
+		},
+	}}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var b bytes.Buffer
+	if err := Write(&b, Markdown, sampleTranscript()); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{"# Karl Havoc\n", "Participants: Karl Havoc\n", "## Mon 3 Mar 2025\n", "## Tue 4 Mar 2025\n", "**Karl Havoc** · 09:00", "> # not a heading\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var b bytes.Buffer
+	if err := Write(&b, JSON, sampleTranscript()); err != nil {
+		t.Fatal(err)
+	}
+	var got []transcriptJSON
+	if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(got) != 1 || got[0].Conversation.ID != convURN.String() || len(got[0].Messages) != 2 {
+		t.Fatalf("unexpected round trip: %+v", got)
+	}
+	if m := got[0].Messages[1]; m.SenderURN != ownURN.String() || !m.IsOwn {
+		t.Errorf("expected the own message with its sender URN, got %+v", m)
+	}
+
+	// The field names are the file format, so pin them
+	for _, want := range []string{`"conversation": {`, `"last_activity_at": `, `"is_own": true`, `"sender_urn": "`, `"participants": [`} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("expected %s in:\n%s", want, b.String())
+		}
+	}
+}
+
+func TestWriteMbox(t *testing.T) {
+	var b bytes.Buffer
+	if err := Write(&b, Mbox, sampleTranscript()); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	if n := strings.Count(out, "\nFrom ") + boolInt(strings.HasPrefix(out, "From ")); n != 2 {
+		t.Errorf("expected 2 message separators, got %d:\n%s", n, out)
+	}
+	for _, want := range []string{
+		"From karl@linkedin.invalid Mon Mar  3 09:00:00 2025\n",
+		`From: "Karl Havoc" <karl@linkedin.invalid>`,
+		"Subject: Karl Havoc\n",
+		"Message-ID: <urn:li:msg:1@linkedin.invalid>\n",
+		"\n>From the top\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for name, want := range map[string]Format{"markdown": Markdown, "MD": Markdown, "json": JSON, "mbox": Mbox} {
+		if got, err := ParseFormat(name); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %v, %v", name, got, err)
+		}
+	}
+	if _, err := ParseFormat("pdf"); err == nil {
+		t.Error("expected an unknown format to fail")
+	}
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func TestFileName(t *testing.T) {
+	tests := map[string]string{
+		"Karl Havoc":         "karl-havoc-2025-03-03.md",
+		"Team: Q3 / Hiring!": "team-q3-hiring-2025-03-03.md",
+		"Zoë":                "zoë-2025-03-03.md",
+		"***":                "conversation-2025-03-03.md",
+	}
+	for title, want := range tests {
+		if got := FileName(title, day, Markdown); got != want {
+			t.Errorf("FileName(%q) = %q, want %q", title, got, want)
+		}
+	}
+}
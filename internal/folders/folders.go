@@ -0,0 +1,119 @@
+package folders
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/ggfevans/endorse/internal/config"
+)
+
+// State holds folder membership that only exists locally. LinkedIn has no
+// pinning or muting, so these are kept on disk by conversation ID.
+type State struct {
+	Pinned map[string]bool `json:"pinned,omitempty"`
+	Muted  map[string]bool `json:"muted,omitempty"`
+}
+
+// Path returns the path to the active profile's folders file.
+func Path() (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "folders.json"), nil
+}
+
+// Load reads folder state from disk, returning an empty state if the file
+// doesn't exist.
+func Load() (State, error) {
+	var s State
+
+	path, err := Path()
+	if err != nil {
+		return s, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return s, err
+	}
+
+	if err := json.Unmarshal(data, &s); err != nil {
+		return s, err
+	}
+
+	return s, nil
+}
+
+// Save writes folder state to disk with restricted permissions.
+func Save(s State) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return config.WriteFile(path, data)
+}
+
+// Clone returns a copy that can be saved off the update loop.
+func (s State) Clone() State {
+	return State{Pinned: cloneSet(s.Pinned), Muted: cloneSet(s.Muted)}
+}
+
+// IsPinned reports whether a conversation is pinned.
+func (s State) IsPinned(id string) bool { return s.Pinned[id] }
+
+// IsMuted reports whether a conversation is muted.
+func (s State) IsMuted(id string) bool { return s.Muted[id] }
+
+// TogglePinned pins or unpins a conversation and returns the new state.
+func (s *State) TogglePinned(id string) bool {
+	return toggle(&s.Pinned, id)
+}
+
+// ToggleMuted mutes or unmutes a conversation and returns the new state.
+func (s *State) ToggleMuted(id string) bool {
+	return toggle(&s.Muted, id)
+}
+
+// Remove forgets a conversation, e.g. once it has been deleted.
+func (s *State) Remove(id string) {
+	delete(s.Pinned, id)
+	delete(s.Muted, id)
+}
+
+func toggle(set *map[string]bool, id string) bool {
+	if (*set)[id] {
+		delete(*set, id)
+		return false
+	}
+	if *set == nil {
+		*set = make(map[string]bool)
+	}
+	(*set)[id] = true
+	return true
+}
+
+func cloneSet(set map[string]bool) map[string]bool {
+	if set == nil {
+		return nil
+	}
+	out := make(map[string]bool, len(set))
+	for k, v := range set {
+		out[k] = v
+	}
+	return out
+}
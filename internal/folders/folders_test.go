@@ -0,0 +1,51 @@
+package folders
+
+import "testing"
+
+func TestToggle(t *testing.T) {
+	var s State
+	if !s.TogglePinned("a") || !s.IsPinned("a") {
+		t.Error("expected a to be pinned after first toggle")
+	}
+	if s.TogglePinned("a") || s.IsPinned("a") {
+		t.Error("expected a to be unpinned after second toggle")
+	}
+
+	s.ToggleMuted("b")
+	s.TogglePinned("b")
+	s.Remove("b")
+	if s.IsMuted("b") || s.IsPinned("b") {
+		t.Error("expected Remove to forget b")
+	}
+}
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	var s State
+	s.TogglePinned("conv-1")
+	s.ToggleMuted("conv-2")
+	if err := Save(s); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !loaded.IsPinned("conv-1") || !loaded.IsMuted("conv-2") || loaded.IsPinned("conv-2") {
+		t.Errorf("expected folder state to round-trip, got %+v", loaded)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	s, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(s.Pinned) != 0 || len(s.Muted) != 0 {
+		t.Errorf("expected empty state, got %+v", s)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -17,6 +18,10 @@ type Client struct {
 	ctx     context.Context
 	ownURN  linkedingo.URN
 	program *tea.Program
+
+	// synced is set once linkedingo holds a sync token, after which
+	// GetConversations returns only conversations changed since the last call
+	synced atomic.Bool
 }
 
 // --- tea.Msg types produced by this client ---
@@ -32,6 +37,8 @@ type AuthFailedMsg struct {
 
 type ConversationsLoadedMsg struct {
 	Conversations []DisplayConversation
+	Older         bool // true when this is an older page fetched by timestamp
+	Full          bool // true when this is the newest page, not just changes since the last sync
 }
 
 type ConversationsLoadFailedMsg struct {
@@ -42,6 +49,7 @@ type MessagesLoadedMsg struct {
 	ConversationID string
 	Messages       []DisplayMessage
 	PrevCursor     string
+	Older          bool // true when this is an older page fetched by cursor
 }
 
 type MessagesLoadFailedMsg struct {
@@ -67,8 +75,15 @@ type RealtimeTypingMsg struct {
 	SenderName     string
 }
 
+// RealtimeSeenMsg reports that a participant has seen a conversation up to
+// and including MessageID.
 type RealtimeSeenMsg struct {
 	ConversationID string
+	MessageID      string
+	ReaderURN      linkedingo.URN
+	ReaderName     string // may be empty if the payload omits the profile
+	SeenAt         time.Time
+	IsOwn          bool // seen by the user on another device
 }
 
 type RealtimeConnectedMsg struct{}
@@ -182,6 +197,7 @@ func New(ctx context.Context, cookie, pageInstance, xLiTrack string) (*Client, e
 		DecoratedEvent:      c.onDecoratedEvent,
 		BadCredentials:      c.onBadCredentials,
 		TransientDisconnect: c.onTransientDisconnect,
+		UnknownError:        c.onUnknownError,
 		ClientConnection:    c.onClientConnection,
 	}
 
@@ -209,6 +225,7 @@ func NewWithURN(ctx context.Context, cookie, pageInstance, xLiTrack string, urn
 		DecoratedEvent:      c.onDecoratedEvent,
 		BadCredentials:      c.onBadCredentials,
 		TransientDisconnect: c.onTransientDisconnect,
+		UnknownError:        c.onUnknownError,
 		ClientConnection:    c.onClientConnection,
 	}
 
@@ -251,17 +268,19 @@ func (c *Client) ValidateAuth() tea.Cmd {
 // FetchConversations fetches the conversation list.
 func (c *Client) FetchConversations() tea.Cmd {
 	return func() tea.Msg {
+		full := !c.synced.Load()
 		resp, err := c.raw.GetConversations(c.ctx)
 		if err != nil {
 			return ConversationsLoadFailedMsg{Err: err}
 		}
+		c.synced.Store(resp.Metadata.NewSyncToken != "")
 
 		var convs []DisplayConversation
 		for _, conv := range resp.Elements {
 			convs = append(convs, ConvertConversation(conv, c.ownURN))
 		}
 
-		return ConversationsLoadedMsg{Conversations: convs}
+		return ConversationsLoadedMsg{Conversations: convs, Full: full}
 	}
 }
 
@@ -278,7 +297,7 @@ func (c *Client) FetchConversationsBefore(before time.Time) tea.Cmd {
 			convs = append(convs, ConvertConversation(conv, c.ownURN))
 		}
 
-		return ConversationsLoadedMsg{Conversations: convs}
+		return ConversationsLoadedMsg{Conversations: convs, Older: true}
 	}
 }
 
@@ -325,6 +344,7 @@ func (c *Client) FetchMessagesWithCursor(conversationURN linkedingo.URN, prevCur
 			ConversationID: conversationURN.String(),
 			Messages:       msgs,
 			PrevCursor:     cursor,
+			Older:          true,
 		}
 	}
 }
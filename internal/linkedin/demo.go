@@ -64,15 +64,15 @@ func (c *DemoClient) FetchConversations() tea.Cmd {
 	return func() tea.Msg {
 		return ConversationsLoadedMsg{
 			Conversations: c.conversations,
+			Full:          true,
 		}
 	}
 }
 
 func (c *DemoClient) FetchConversationsBefore(_ time.Time) tea.Cmd {
+	// The demo inbox fits in a single page, so there is never an older page.
 	return func() tea.Msg {
-		return ConversationsLoadedMsg{
-			Conversations: c.conversations,
-		}
+		return ConversationsLoadedMsg{Older: true}
 	}
 }
 
@@ -88,12 +88,12 @@ func (c *DemoClient) FetchMessages(conversationURN linkedingo.URN, _ time.Time,
 }
 
 func (c *DemoClient) FetchMessagesWithCursor(conversationURN linkedingo.URN, _ string, _ int) tea.Cmd {
+	// Demo threads fit in a single page, so there is never an older page.
 	convID := conversationURN.String()
-	msgs := c.messages[convID]
 	return func() tea.Msg {
 		return MessagesLoadedMsg{
 			ConversationID: convID,
-			Messages:       msgs,
+			Older:          true,
 		}
 	}
 }
@@ -115,7 +115,7 @@ func (c *DemoClient) SendMessage(conversationURN linkedingo.URN, text string) te
 	}
 
 	// Schedule auto-reply
-	c.scheduleAutoReply(convID)
+	c.scheduleAutoReply(convID, msgID)
 
 	return func() tea.Msg {
 		return MessageSentMsg{
@@ -125,7 +125,7 @@ func (c *DemoClient) SendMessage(conversationURN linkedingo.URN, text string) te
 	}
 }
 
-func (c *DemoClient) scheduleAutoReply(convID string) {
+func (c *DemoClient) scheduleAutoReply(convID, sentID string) {
 	c.mu.Lock()
 	replies, ok := c.autoReplies[convID]
 	if !ok || len(replies) == 0 {
@@ -158,6 +158,23 @@ func (c *DemoClient) scheduleAutoReply(convID string) {
 	}
 	c.mu.Unlock()
 
+	// Mark the sent message seen 500ms after user sends
+	seenTimer := time.AfterFunc(500*time.Millisecond, func() {
+		c.mu.Lock()
+		p := c.program
+		c.mu.Unlock()
+		if p == nil {
+			return
+		}
+		p.Send(RealtimeSeenMsg{
+			ConversationID: convID,
+			MessageID:      sentID,
+			ReaderURN:      senderURN,
+			ReaderName:     senderName,
+			SeenAt:         time.Now(),
+		})
+	})
+
 	// Send typing indicator 800ms after user sends
 	typingTimer := time.AfterFunc(800*time.Millisecond, func() {
 		c.mu.Lock()
@@ -194,7 +211,7 @@ func (c *DemoClient) scheduleAutoReply(convID string) {
 	})
 
 	c.mu.Lock()
-	c.timers = append(c.timers, typingTimer, timer)
+	c.timers = append(c.timers, seenTimer, typingTimer, timer)
 	c.mu.Unlock()
 }
 
@@ -202,6 +219,7 @@ func (c *DemoClient) MarkRead(urn linkedingo.URN) tea.Cmd {
 	for i := range c.conversations {
 		if c.conversations[i].URN == urn {
 			c.conversations[i].Unread = false
+			c.conversations[i].UnreadCount = 0
 			break
 		}
 	}
@@ -35,6 +35,7 @@ func buildDemoConversations() []DisplayConversation {
 			LastMessage:    "Do you think they know I'm just a guy?",
 			LastActivityAt: now.Add(-2 * time.Minute),
 			Unread:         true,
+			UnreadCount:    2,
 			URN:            demoConvKarlURN,
 			Participants: []DisplayParticipant{
 				{Name: "Karl Havoc", URN: demoKarlURN},
@@ -47,6 +48,7 @@ func buildDemoConversations() []DisplayConversation {
 			LastMessage:    "I'm done. This is the maddest I've ever been",
 			LastActivityAt: now.Add(-8 * time.Minute),
 			Unread:         true,
+			UnreadCount:    3,
 			URN:            demoConvTammyURN,
 			Participants: []DisplayParticipant{
 				{Name: "Tammy Craps", URN: demoTammyURN},
@@ -83,6 +85,7 @@ func buildDemoConversations() []DisplayConversation {
 			LastMessage:    "They're turbo toilets. The water goes the other way",
 			LastActivityAt: now.Add(-1 * time.Hour),
 			Unread:         true,
+			UnreadCount:    1,
 			URN:            demoConvDanURN,
 			Participants: []DisplayParticipant{
 				{Name: "Dan Vega", URN: demoDanURN},
@@ -95,6 +98,7 @@ func buildDemoConversations() []DisplayConversation {
 			LastMessage:    "I know that last one isn't a real skill but I think if enough people endorse me they'll have to add it",
 			LastActivityAt: now.Add(-2 * time.Hour),
 			Unread:         false,
+			Archived:       true,
 			URN:            demoConvPattiURN,
 			Participants: []DisplayParticipant{
 				{Name: "Patti Harrison", URN: demoPattiURN},
@@ -107,6 +111,7 @@ func buildDemoConversations() []DisplayConversation {
 			LastMessage:    "The bones are their money. So are the worms",
 			LastActivityAt: now.Add(-30 * time.Minute),
 			Unread:         true,
+			UnreadCount:    4,
 			URN:            demoConvCoryURN,
 			Participants: []DisplayParticipant{
 				{Name: "Cory", URN: demoCoryURN},
@@ -45,8 +45,14 @@ func (c *Client) onDecoratedEvent(ctx context.Context, evt *linkedingo.Decorated
 		if convID == "" {
 			convID = receipt.Message.BackendConversationURN.String()
 		}
+		reader := ConvertParticipant(receipt.SeenByParticipant, c.ownURN)
 		c.program.Send(RealtimeSeenMsg{
 			ConversationID: convID,
+			MessageID:      receipt.Message.EntityURN.String(),
+			ReaderURN:      reader.URN,
+			ReaderName:     reader.Name,
+			SeenAt:         receipt.SeenAt.Time,
+			IsOwn:          reader.IsOwnUser,
 		})
 	}
 }
@@ -67,6 +73,15 @@ func (c *Client) onTransientDisconnect(_ context.Context, err error) {
 	c.program.Send(RealtimeDisconnectedMsg{Err: err})
 }
 
+// onUnknownError handles the realtime loop giving up after repeated
+// failures. It is reported as a disconnect so the app can reconnect.
+func (c *Client) onUnknownError(_ context.Context, err error) {
+	if c.program == nil {
+		return
+	}
+	c.program.Send(RealtimeDisconnectedMsg{Err: err})
+}
+
 // onClientConnection handles successful connection events.
 func (c *Client) onClientConnection(_ context.Context, _ *linkedingo.ClientConnection) {
 	if c.program == nil {
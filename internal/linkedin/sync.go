@@ -0,0 +1,109 @@
+package linkedin
+
+import (
+	"fmt"
+	"time"
+
+	"go.mau.fi/mautrix-linkedin/pkg/linkedingo"
+)
+
+// Sync calls a MessagingClient synchronously, for use outside Bubble Tea.
+// Each method runs the client's command in the calling goroutine and
+// unpacks the resulting message into a value or an error.
+type Sync struct {
+	client MessagingClient
+}
+
+// NewSync wraps a client for synchronous use.
+func NewSync(client MessagingClient) *Sync {
+	return &Sync{client: client}
+}
+
+// ValidateAuth checks the credentials and returns the signed-in username
+// and URN. Validating isn't enough to use a real client built without a
+// URN: its mailbox is fixed when it's built, so rebuild it with NewWithURN
+// and the URN returned here before making other calls.
+func (s *Sync) ValidateAuth() (string, linkedingo.URN, error) {
+	switch msg := s.client.ValidateAuth()().(type) {
+	case AuthValidatedMsg:
+		return msg.Username, msg.UserURN, nil
+	case AuthFailedMsg:
+		return "", linkedingo.URN{}, msg.Err
+	default:
+		return "", linkedingo.URN{}, unexpected(msg)
+	}
+}
+
+// Conversations returns the most recent page of conversations.
+func (s *Sync) Conversations() ([]DisplayConversation, error) {
+	switch msg := s.client.FetchConversations()().(type) {
+	case ConversationsLoadedMsg:
+		return msg.Conversations, nil
+	case ConversationsLoadFailedMsg:
+		return nil, msg.Err
+	default:
+		return nil, unexpected(msg)
+	}
+}
+
+// ConversationsBefore returns the page of conversations last active before t.
+func (s *Sync) ConversationsBefore(t time.Time) ([]DisplayConversation, error) {
+	switch msg := s.client.FetchConversationsBefore(t)().(type) {
+	case ConversationsLoadedMsg:
+		return msg.Conversations, nil
+	case ConversationsLoadFailedMsg:
+		return nil, msg.Err
+	default:
+		return nil, unexpected(msg)
+	}
+}
+
+// Messages returns up to count of the newest messages in a conversation,
+// with the cursor for the page before them.
+func (s *Sync) Messages(conversationURN linkedingo.URN, count int) ([]DisplayMessage, string, error) {
+	return s.messages(s.client.FetchMessages(conversationURN, time.Now(), count)())
+}
+
+// MessagesBefore returns the page of messages before a cursor from Messages.
+func (s *Sync) MessagesBefore(conversationURN linkedingo.URN, prevCursor string, count int) ([]DisplayMessage, string, error) {
+	return s.messages(s.client.FetchMessagesWithCursor(conversationURN, prevCursor, count)())
+}
+
+func (s *Sync) messages(result any) ([]DisplayMessage, string, error) {
+	switch msg := result.(type) {
+	case MessagesLoadedMsg:
+		return msg.Messages, msg.PrevCursor, nil
+	case MessagesLoadFailedMsg:
+		return nil, "", msg.Err
+	default:
+		return nil, "", unexpected(msg)
+	}
+}
+
+// Send sends a text message and returns it as the server recorded it.
+func (s *Sync) Send(conversationURN linkedingo.URN, text string) (DisplayMessage, error) {
+	switch msg := s.client.SendMessage(conversationURN, text)().(type) {
+	case MessageSentMsg:
+		return msg.Message, nil
+	case MessageSendFailedMsg:
+		return DisplayMessage{}, msg.Err
+	default:
+		return DisplayMessage{}, unexpected(msg)
+	}
+}
+
+// MarkRead marks a conversation as read.
+func (s *Sync) MarkRead(conversationURN linkedingo.URN) error {
+	switch msg := s.client.MarkRead(conversationURN)().(type) {
+	case nil:
+		return nil
+	case MarkReadFailedMsg:
+		return msg.Err
+	default:
+		return unexpected(msg)
+	}
+}
+
+func unexpected(msg any) error {
+	return fmt.Errorf("unexpected response %T", msg)
+}
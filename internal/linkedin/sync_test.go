@@ -0,0 +1,68 @@
+package linkedin
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"go.mau.fi/mautrix-linkedin/pkg/linkedingo"
+)
+
+func TestSyncOverDemoClient(t *testing.T) {
+	s := NewSync(NewDemoClient())
+
+	if name, urn, err := s.ValidateAuth(); err != nil || name == "" || urn.IsEmpty() {
+		t.Fatalf("ValidateAuth() = %q, %q, %v", name, urn, err)
+	}
+
+	convs, err := s.Conversations()
+	if err != nil || len(convs) == 0 {
+		t.Fatalf("Conversations() = %d conversations, %v", len(convs), err)
+	}
+
+	msgs, _, err := s.Messages(convs[0].URN, 20)
+	if err != nil || len(msgs) == 0 {
+		t.Fatalf("Messages() = %d messages, %v", len(msgs), err)
+	}
+
+	sent, err := s.Send(convs[0].URN, "hello from a script")
+	if err != nil || sent.Body != "hello from a script" || !sent.IsOwn {
+		t.Errorf("Send() = %+v, %v", sent, err)
+	}
+
+	if err := s.MarkRead(convs[0].URN); err != nil {
+		t.Errorf("MarkRead() = %v", err)
+	}
+}
+
+// failingClient reports failures for every call.
+type failingClient struct{ *DemoClient }
+
+var errBoom = errors.New("boom")
+
+func (failingClient) FetchConversations() tea.Cmd {
+	return func() tea.Msg { return ConversationsLoadFailedMsg{Err: errBoom} }
+}
+
+func (failingClient) SendMessage(linkedingo.URN, string) tea.Cmd {
+	return func() tea.Msg { return MessageSendFailedMsg{Err: errBoom} }
+}
+
+func (failingClient) FetchMessages(linkedingo.URN, time.Time, int) tea.Cmd {
+	return func() tea.Msg { return AuthFailedMsg{Err: errBoom} }
+}
+
+func TestSyncSurfacesErrors(t *testing.T) {
+	s := NewSync(failingClient{NewDemoClient()})
+
+	if _, err := s.Conversations(); !errors.Is(err, errBoom) {
+		t.Errorf("Conversations() error = %v, want %v", err, errBoom)
+	}
+	if _, err := s.Send(linkedingo.URN{}, "hi"); !errors.Is(err, errBoom) {
+		t.Errorf("Send() error = %v, want %v", err, errBoom)
+	}
+	if _, _, err := s.Messages(linkedingo.URN{}, 1); err == nil {
+		t.Error("expected an unexpected response type to be an error")
+	}
+}
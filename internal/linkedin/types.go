@@ -1,11 +1,10 @@
 package linkedin
 
 import (
+	"slices"
 	"time"
 
 	"go.mau.fi/mautrix-linkedin/pkg/linkedingo"
-
-	"github.com/ggfevans/endorse/internal/util"
 )
 
 // DisplayConversation is a display-friendly version of a LinkedIn conversation.
@@ -15,10 +14,16 @@ type DisplayConversation struct {
 	LastMessage    string
 	LastActivityAt time.Time
 	Unread         bool
+	UnreadCount    int  // unread messages; may be 0 for a manually unread conversation
+	Archived       bool // filed under LinkedIn's archive category
 	Participants   []DisplayParticipant
 	URN            linkedingo.URN
 }
 
+// CategoryArchive is the LinkedIn conversation category for archived
+// conversations.
+const CategoryArchive = "ARCHIVE"
+
 // DisplayParticipant is a display-friendly participant.
 type DisplayParticipant struct {
 	Name      string
@@ -46,6 +51,7 @@ func ConvertConversation(conv linkedingo.Conversation, ownURN linkedingo.URN) Di
 		Title:          conv.Title,
 		LastActivityAt: conv.LastActivityAt.Time,
 		Unread:         !conv.Read,
+		Archived:       slices.Contains(conv.Categories, CategoryArchive),
 		URN:            conv.EntityURN,
 	}
 
@@ -67,6 +73,10 @@ func ConvertConversation(conv linkedingo.Conversation, ownURN linkedingo.URN) Di
 		dc.Title = "Conversation"
 	}
 
+	if dc.Unread {
+		dc.UnreadCount = unreadCount(conv, ownURN)
+	}
+
 	// Extract last message preview
 	if len(conv.Messages.Elements) > 0 {
 		last := conv.Messages.Elements[len(conv.Messages.Elements)-1]
@@ -76,6 +86,32 @@ func ConvertConversation(conv linkedingo.Conversation, ownURN linkedingo.URN) Di
 	return dc
 }
 
+// UnreadMessages returns how many messages to count as unread, treating a
+// conversation flagged unread without a known count as one.
+func (dc DisplayConversation) UnreadMessages() int {
+	if dc.Unread && dc.UnreadCount == 0 {
+		return 1
+	}
+	return dc.UnreadCount
+}
+
+// unreadCount counts the messages from others delivered since the user last
+// read the conversation. linkedingo doesn't decode the payload's unreadCount
+// field, so this is derived from the messages included with the
+// conversation, and is at least one since the conversation is unread.
+func unreadCount(conv linkedingo.Conversation, ownURN linkedingo.URN) int {
+	count := 0
+	for _, msg := range conv.Messages.Elements {
+		if msg.Sender.EntityURN.ID() == ownURN.ID() {
+			continue
+		}
+		if msg.DeliveredAt.Time.After(conv.LastReadAt.Time) {
+			count++
+		}
+	}
+	return max(count, 1)
+}
+
 // ConvertParticipant converts a linkedingo MessagingParticipant to display type.
 func ConvertParticipant(p linkedingo.MessagingParticipant, ownURN linkedingo.URN) DisplayParticipant {
 	dp := DisplayParticipant{
@@ -117,11 +153,11 @@ func ConvertMessage(msg linkedingo.Message, ownURN linkedingo.URN) DisplayMessag
 }
 
 // ToConvListItem converts a DisplayConversation to a convlist.Conversation.
-func (dc DisplayConversation) ToConvListItem() (id, name, lastMsg, timestamp string, unread bool) {
-	return dc.ID, dc.Title, dc.LastMessage, util.RelativeTime(dc.LastActivityAt), dc.Unread
+func (dc DisplayConversation) ToConvListItem() (id, name, lastMsg string, timestamp time.Time, unread bool) {
+	return dc.ID, dc.Title, dc.LastMessage, dc.LastActivityAt, dc.Unread
 }
 
 // ToThreadMessage converts a DisplayMessage to a thread.Message.
-func (dm DisplayMessage) ToThreadMessage() (id, sender, body, timestamp string, isOwn bool) {
-	return dm.ID, dm.Sender, dm.Body, util.RelativeTime(dm.Timestamp), dm.IsOwn
+func (dm DisplayMessage) ToThreadMessage() (id, sender, body string, timestamp time.Time, isOwn bool) {
+	return dm.ID, dm.Sender, dm.Body, dm.Timestamp, dm.IsOwn
 }
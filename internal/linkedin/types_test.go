@@ -161,3 +161,53 @@ func TestConvertConversation_TitleFromNonOwnParticipant(t *testing.T) {
 		t.Errorf("expected Title %q (non-own participant), got %q", expected, dc.Title)
 	}
 }
+
+func TestConvertConversation_Archived(t *testing.T) {
+	conv := linkedingo.Conversation{
+		EntityURN:  linkedingo.NewURN("urn:li:msg_conversation:100"),
+		Categories: []string{"PRIMARY_INBOX", CategoryArchive},
+	}
+	if dc := ConvertConversation(conv, linkedingo.URN{}); !dc.Archived {
+		t.Error("expected a conversation in the archive category to be archived")
+	}
+
+	conv.Categories = []string{"PRIMARY_INBOX"}
+	if dc := ConvertConversation(conv, linkedingo.URN{}); dc.Archived {
+		t.Error("expected an inbox conversation not to be archived")
+	}
+}
+
+func TestConvertConversation_UnreadCount(t *testing.T) {
+	ownURN := linkedingo.NewURN("urn:li:fsd_profile:123")
+	readAt := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	msgAt := func(sender string, offset time.Duration) linkedingo.Message {
+		return linkedingo.Message{
+			DeliveredAt: jsontime.UnixMilli{Time: readAt.Add(offset)},
+			Sender:      makeMemberParticipant(sender, "Someone", "Else"),
+		}
+	}
+
+	conv := linkedingo.Conversation{
+		EntityURN:  linkedingo.NewURN("urn:li:msg_conversation:100"),
+		LastReadAt: jsontime.UnixMilli{Time: readAt},
+		Read:       false,
+		Messages: linkedingo.CollectionResponse[any, linkedingo.Message]{
+			Elements: []linkedingo.Message{
+				msgAt("urn:li:fsd_profile:456", -time.Hour), // already read
+				msgAt("urn:li:fsd_profile:456", time.Minute),
+				msgAt("urn:li:fsd_profile:123", 2*time.Minute), // own reply
+				msgAt("urn:li:fsd_profile:456", 3*time.Minute),
+			},
+		},
+	}
+
+	dc := ConvertConversation(conv, ownURN)
+	if !dc.Unread || dc.UnreadCount != 2 {
+		t.Errorf("expected 2 unread messages, got Unread=%v UnreadCount=%d", dc.Unread, dc.UnreadCount)
+	}
+
+	conv.Read = true
+	if dc := ConvertConversation(conv, ownURN); dc.UnreadCount != 0 {
+		t.Errorf("expected no unread count for a read conversation, got %d", dc.UnreadCount)
+	}
+}
@@ -0,0 +1,221 @@
+package outbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.mau.fi/mautrix-linkedin/pkg/linkedingo"
+
+	"github.com/ggfevans/endorse/internal/config"
+)
+
+// MaxAttempts is how many automatic sends are tried before an item is
+// marked failed and left for the user to retry or discard.
+const MaxAttempts = 5
+
+const (
+	baseBackoff = 2 * time.Second
+	maxBackoff  = 5 * time.Minute
+)
+
+// Item is a message that has not been confirmed as sent.
+type Item struct {
+	ID              string         `json:"id"`
+	ConversationID  string         `json:"conversation_id"`
+	ConversationURN linkedingo.URN `json:"conversation_urn"`
+	Text            string         `json:"text"`
+	CreatedAt       time.Time      `json:"created_at"`
+	Attempts        int            `json:"attempts"`
+	NextAttempt     time.Time      `json:"next_attempt"`
+	LastError       string         `json:"last_error,omitempty"`
+	Failed          bool           `json:"failed"` // automatic retries exhausted
+
+	Sending bool `json:"-"` // request in flight; never persisted
+}
+
+// Outbox holds unsent messages across all conversations, oldest first.
+type Outbox struct {
+	Items []Item `json:"items"`
+	seq   int
+}
+
+// Backoff returns the delay before the given retry attempt (1-based),
+// doubling from two seconds up to five minutes.
+func Backoff(attempt int) time.Duration {
+	d := baseBackoff
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if d >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return d
+}
+
+// Path returns the path to the active profile's outbox file.
+func Path() (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "outbox.json"), nil
+}
+
+// Load reads the outbox from disk, returning an empty outbox if the file
+// doesn't exist.
+func Load() (Outbox, error) {
+	var o Outbox
+
+	path, err := Path()
+	if err != nil {
+		return o, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return o, nil
+		}
+		return o, err
+	}
+
+	if err := json.Unmarshal(data, &o); err != nil {
+		return o, err
+	}
+
+	return o, nil
+}
+
+// Save writes the outbox to disk with restricted permissions, replacing the
+// file atomically. An empty outbox removes the file.
+func Save(o Outbox) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+
+	if len(o.Items) == 0 {
+		err = os.Remove(path)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(o, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return config.WriteFile(path, data)
+}
+
+// Clone returns a copy that can be saved off the update loop.
+func (o Outbox) Clone() Outbox {
+	return Outbox{Items: append([]Item(nil), o.Items...), seq: o.seq}
+}
+
+// Add queues a new message and returns it. It isn't marked as sending until
+// MarkSending, since there may be no client to send it with yet.
+func (o *Outbox) Add(convID string, urn linkedingo.URN, text string, now time.Time) Item {
+	o.seq++
+	item := Item{
+		ID:              fmt.Sprintf("local-%d-%d", now.UnixNano(), o.seq),
+		ConversationID:  convID,
+		ConversationURN: urn,
+		Text:            text,
+		CreatedAt:       now,
+	}
+	o.Items = append(o.Items, item)
+	return item
+}
+
+// Get returns the item with the given ID.
+func (o Outbox) Get(id string) (Item, bool) {
+	for _, it := range o.Items {
+		if it.ID == id {
+			return it, true
+		}
+	}
+	return Item{}, false
+}
+
+// Remove drops an item, e.g. once it has been sent or discarded.
+func (o *Outbox) Remove(id string) {
+	for i, it := range o.Items {
+		if it.ID == id {
+			o.Items = append(o.Items[:i:i], o.Items[i+1:]...)
+			return
+		}
+	}
+}
+
+// MarkSending flags an item as in flight and clears its failed state so a
+// manual retry gets a fresh round of automatic attempts.
+func (o *Outbox) MarkSending(id string, manual bool) (Item, bool) {
+	for i := range o.Items {
+		if o.Items[i].ID == id {
+			o.Items[i].Sending = true
+			if manual {
+				o.Items[i].Failed = false
+				o.Items[i].Attempts = 0
+			}
+			return o.Items[i], true
+		}
+	}
+	return Item{}, false
+}
+
+// MarkFailed records a failed attempt and schedules the next one, or marks
+// the item failed once MaxAttempts is reached.
+func (o *Outbox) MarkFailed(id string, err error, now time.Time) (Item, bool) {
+	for i := range o.Items {
+		if o.Items[i].ID != id {
+			continue
+		}
+		it := &o.Items[i]
+		it.Sending = false
+		it.Attempts++
+		if err != nil {
+			it.LastError = err.Error()
+		}
+		if it.Attempts >= MaxAttempts {
+			it.Failed = true
+			it.NextAttempt = time.Time{}
+		} else {
+			it.NextAttempt = now.Add(Backoff(it.Attempts))
+		}
+		return *it, true
+	}
+	return Item{}, false
+}
+
+// InFlight returns the oldest item being sent to a conversation with the
+// given text, so a message echoed back before its send result can be matched
+// to the entry it came from.
+func (o Outbox) InFlight(convID, text string) (Item, bool) {
+	for _, it := range o.Items {
+		if it.Sending && it.ConversationID == convID && it.Text == text {
+			return it, true
+		}
+	}
+	return Item{}, false
+}
+
+// ForConversation returns the unsent items for a conversation, oldest first.
+func (o Outbox) ForConversation(convID string) []Item {
+	var items []Item
+	for _, it := range o.Items {
+		if it.ConversationID == convID {
+			items = append(items, it)
+		}
+	}
+	return items
+}
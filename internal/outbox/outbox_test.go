@@ -0,0 +1,138 @@
+package outbox
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go.mau.fi/mautrix-linkedin/pkg/linkedingo"
+)
+
+var testURN = linkedingo.NewURN("urn:li:msg_conversation:abc")
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 2 * time.Second},
+		{2, 4 * time.Second},
+		{3, 8 * time.Second},
+		{20, 5 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := Backoff(tt.attempt); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestMarkFailedSchedulesRetryThenGivesUp(t *testing.T) {
+	var o Outbox
+	now := time.Now()
+	item := o.Add(testURN.String(), testURN, "hello", now)
+	if item.Sending {
+		t.Error("expected a new item not to be sending until MarkSending")
+	}
+
+	got, ok := o.MarkFailed(item.ID, errors.New("network down"), now)
+	if !ok {
+		t.Fatal("expected MarkFailed to find the item")
+	}
+	if got.Sending || got.Failed || got.Attempts != 1 {
+		t.Errorf("expected one retrying attempt, got %+v", got)
+	}
+	if !got.NextAttempt.Equal(now.Add(Backoff(1))) {
+		t.Errorf("expected next attempt after backoff, got %v", got.NextAttempt)
+	}
+	if got.LastError != "network down" {
+		t.Errorf("expected last error recorded, got %q", got.LastError)
+	}
+
+	for i := 1; i < MaxAttempts; i++ {
+		got, _ = o.MarkFailed(item.ID, errors.New("still down"), now)
+	}
+	if !got.Failed {
+		t.Errorf("expected item failed after %d attempts, got %+v", MaxAttempts, got)
+	}
+
+	got, _ = o.MarkSending(item.ID, true)
+	if got.Failed || got.Attempts != 0 || !got.Sending {
+		t.Errorf("expected manual retry to reset attempts, got %+v", got)
+	}
+}
+
+func TestForConversationAndRemove(t *testing.T) {
+	var o Outbox
+	other := linkedingo.NewURN("urn:li:msg_conversation:other")
+	now := time.Now()
+	a := o.Add(testURN.String(), testURN, "one", now)
+	o.Add(other.String(), other, "two", now)
+	o.Add(testURN.String(), testURN, "three", now)
+
+	items := o.ForConversation(testURN.String())
+	if len(items) != 2 || items[0].Text != "one" || items[1].Text != "three" {
+		t.Fatalf("expected oldest-first items for the conversation, got %+v", items)
+	}
+
+	o.Remove(a.ID)
+	if _, ok := o.Get(a.ID); ok {
+		t.Error("expected removed item to be gone")
+	}
+	if len(o.Items) != 2 {
+		t.Errorf("expected 2 items after remove, got %d", len(o.Items))
+	}
+}
+
+func TestInFlight(t *testing.T) {
+	var o Outbox
+	now := time.Now()
+	queued := o.Add(testURN.String(), testURN, "same", now)
+	sending := o.Add(testURN.String(), testURN, "same", now)
+	o.MarkSending(sending.ID, false)
+
+	if got, ok := o.InFlight(testURN.String(), "same"); !ok || got.ID != sending.ID {
+		t.Errorf("expected the in-flight item, got %+v (ok=%v)", got, ok)
+	}
+	if _, ok := o.InFlight(testURN.String(), "other"); ok {
+		t.Error("expected no match for different text")
+	}
+	o.Remove(sending.ID)
+	if _, ok := o.InFlight(testURN.String(), "same"); ok {
+		t.Errorf("expected a queued item %s not to match until it is sent", queued.ID)
+	}
+}
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	var o Outbox
+	item := o.Add(testURN.String(), testURN, "persist me", time.Now())
+	if err := Save(o); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	got, ok := loaded.Get(item.ID)
+	if !ok {
+		t.Fatal("expected item to survive a restart")
+	}
+	if got.Sending {
+		t.Error("expected in-flight state not to be persisted")
+	}
+	if got.ConversationURN != testURN || got.Text != "persist me" {
+		t.Errorf("expected item fields to round-trip, got %+v", got)
+	}
+
+	// Saving an empty outbox removes the file
+	if err := Save(Outbox{}); err != nil {
+		t.Fatalf("Save(empty) error = %v", err)
+	}
+	loaded, err = Load()
+	if err != nil || len(loaded.Items) != 0 {
+		t.Errorf("expected empty outbox after clearing, got %+v (err %v)", loaded.Items, err)
+	}
+}
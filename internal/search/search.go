@@ -0,0 +1,266 @@
+package search
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ggfevans/endorse/internal/linkedin"
+)
+
+// MaxResults caps how many hits a single query returns.
+const MaxResults = 50
+
+// snippetRadius is how many runes of context are kept either side of a
+// message body match.
+const snippetRadius = 30
+
+// Match quality for a single term within a field.
+const (
+	matchNone      = 0
+	matchSubstring = 1
+	matchWordStart = 2
+	matchPrefix    = 3
+)
+
+// Conversation hits rank above message hits of the same quality, since a
+// title or participant match is usually what the user is looking for.
+const (
+	conversationWeight = 2.0
+	messageWeight      = 1.0
+)
+
+// Result is a single search hit.
+type Result struct {
+	ConversationID string
+	MessageID      string // empty for title/participant hits
+	Title          string
+	Snippet        string
+	Time           time.Time
+	Score          float64
+}
+
+// Terms splits a query into lowercase search terms.
+func Terms(query string) []string {
+	return strings.Fields(strings.ToLower(query))
+}
+
+// Search ranks conversations and locally known messages against the query.
+// Every term must match for a conversation or message to be a hit. Results
+// are ordered by match quality, with recency as a bonus and tie-breaker.
+func Search(query string, convs []linkedin.DisplayConversation, messages map[string][]linkedin.DisplayMessage, now time.Time) []Result {
+	terms := Terms(query)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	var results []Result
+	for _, dc := range convs {
+		names := []string{dc.Title}
+		for _, p := range dc.Participants {
+			if !p.IsOwnUser {
+				names = append(names, p.Name)
+			}
+		}
+		if q := quality(strings.Join(names, " "), terms); q > 0 {
+			results = append(results, Result{
+				ConversationID: dc.ID,
+				Title:          dc.Title,
+				Snippet:        dc.LastMessage,
+				Time:           dc.LastActivityAt,
+				Score:          float64(q)*conversationWeight + recency(dc.LastActivityAt, now),
+			})
+		}
+
+		for _, dm := range messages[dc.ID] {
+			if q := quality(dm.Body, terms); q > 0 {
+				results = append(results, Result{
+					ConversationID: dc.ID,
+					MessageID:      dm.ID,
+					Title:          dc.Title,
+					Snippet:        snippet(dm.Body, terms[0]),
+					Time:           dm.Timestamp,
+					Score:          float64(q)*messageWeight + recency(dm.Timestamp, now),
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Time.After(results[j].Time)
+	})
+
+	if len(results) > MaxResults {
+		results = results[:MaxResults]
+	}
+	return results
+}
+
+// quality returns the summed match quality of all terms in text, or zero if
+// any term is missing.
+func quality(text string, terms []string) int {
+	text = strings.ToLower(text)
+	total := 0
+	for _, term := range terms {
+		q := termQuality(text, term)
+		if q == matchNone {
+			return 0
+		}
+		total += q
+	}
+	return total
+}
+
+func termQuality(text, term string) int {
+	if strings.HasPrefix(text, term) {
+		return matchPrefix
+	}
+	best := matchNone
+	for i := strings.Index(text, term); i >= 0; {
+		if prev, _ := utf8.DecodeLastRuneInString(text[:i]); !isWordRune(prev) {
+			return matchWordStart
+		}
+		best = matchSubstring
+		next := strings.Index(text[i+1:], term)
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+	return best
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// recency returns a bonus in (0, 1] that decays with age: 1 for now,
+// 1/2 for a day old, 1/3 for two days old, and so on.
+func recency(t, now time.Time) float64 {
+	if t.IsZero() {
+		return 0
+	}
+	days := now.Sub(t).Hours() / 24
+	if days < 0 {
+		days = 0
+	}
+	return 1 / (1 + days)
+}
+
+// snippet returns the part of body surrounding the first match of term.
+func snippet(body, term string) string {
+	body = strings.Join(strings.Fields(body), " ")
+	runes := []rune(body)
+	lower := []rune(strings.ToLower(body))
+
+	idx := strings.Index(string(lower), term)
+	if idx < 0 {
+		return body
+	}
+	// Convert byte offset in the lowered string to a rune offset
+	start := min(len([]rune(string(lower)[:idx])), len(runes))
+
+	from := max(start-snippetRadius, 0)
+	to := min(start+len([]rune(term))+snippetRadius, len(runes))
+
+	out := string(runes[from:to])
+	if from > 0 {
+		out = "…" + out
+	}
+	if to < len(runes) {
+		out += "…"
+	}
+	return out
+}
+
+// Ranges returns the byte ranges of every case-insensitive occurrence of the
+// terms in text, sorted and merged, for highlighting.
+func Ranges(text string, terms []string) [][2]int {
+	lower := strings.ToLower(text)
+	if len(lower) != len(text) {
+		// Lowercasing changed byte lengths; offsets would not line up
+		return nil
+	}
+
+	var ranges [][2]int
+	for _, term := range terms {
+		if term == "" {
+			continue
+		}
+		for i := 0; ; {
+			j := strings.Index(lower[i:], term)
+			if j < 0 {
+				break
+			}
+			ranges = append(ranges, [2]int{i + j, i + j + len(term)})
+			i += j + len(term)
+		}
+	}
+
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i][0] < ranges[j][0] })
+	var merged [][2]int
+	for _, r := range ranges {
+		if n := len(merged); n > 0 && r[0] <= merged[n-1][1] {
+			merged[n-1][1] = max(merged[n-1][1], r[1])
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// Fuzzy scores text against a query whose terms may skip characters, so
+// "jsmi" matches "John Smith". Every term must match. Runs of adjacent
+// characters and matches at the start of words score higher. It returns
+// zero if the text doesn't match.
+func Fuzzy(query, text string) int {
+	terms := Terms(query)
+	if len(terms) == 0 {
+		return 0
+	}
+	runes := []rune(strings.ToLower(text))
+	total := 0
+	for _, term := range terms {
+		score := fuzzyTerm(runes, []rune(term))
+		if score == 0 {
+			return 0
+		}
+		total += score
+	}
+	return total
+}
+
+// fuzzyTerm returns the best score of a term matched in order against text,
+// trying each place the term's first character appears.
+func fuzzyTerm(text, term []rune) int {
+	best := 0
+	for start, r := range text {
+		if r != term[0] {
+			continue
+		}
+		score, prev, j := 0, -2, 0
+		for i := start; i < len(text) && j < len(term); i++ {
+			if text[i] != term[j] {
+				continue
+			}
+			score++
+			if i == prev+1 {
+				score += 2
+			}
+			if i == 0 || !isWordRune(text[i-1]) {
+				score += 2
+			}
+			prev = i
+			j++
+		}
+		if j == len(term) {
+			best = max(best, score)
+		}
+	}
+	return best
+}
@@ -0,0 +1,136 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ggfevans/endorse/internal/linkedin"
+)
+
+func TestSearchRanksTitleAboveBody(t *testing.T) {
+	now := time.Now()
+	convs := []linkedin.DisplayConversation{
+		{ID: "c1", Title: "Alice Johnson", LastActivityAt: now},
+		{ID: "c2", Title: "Bob Smith", LastActivityAt: now},
+	}
+	messages := map[string][]linkedin.DisplayMessage{
+		"c2": {{ID: "m1", Body: "Have you met alice yet?", Timestamp: now}},
+	}
+
+	results := Search("alice", convs, messages, now)
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].ConversationID != "c1" || results[0].MessageID != "" {
+		t.Errorf("expected title hit first, got %+v", results[0])
+	}
+	if results[1].MessageID != "m1" {
+		t.Errorf("expected message hit second, got %+v", results[1])
+	}
+}
+
+func TestSearchMatchesParticipants(t *testing.T) {
+	convs := []linkedin.DisplayConversation{
+		{ID: "c1", Title: "Hiring sync", Participants: []linkedin.DisplayParticipant{
+			{Name: "Carol White"},
+			{Name: "Me", IsOwnUser: true},
+		}},
+	}
+
+	if results := Search("carol", convs, nil, time.Now()); len(results) != 1 {
+		t.Errorf("expected participant name to match, got %d results", len(results))
+	}
+	if results := Search("me", convs, nil, time.Now()); len(results) != 0 {
+		t.Errorf("expected own user not to be searchable, got %d results", len(results))
+	}
+}
+
+func TestSearchRequiresAllTerms(t *testing.T) {
+	convs := []linkedin.DisplayConversation{{ID: "c1", Title: "Alice Johnson"}}
+
+	if results := Search("alice smith", convs, nil, time.Now()); len(results) != 0 {
+		t.Errorf("expected no results when a term is missing, got %d", len(results))
+	}
+	if results := Search("JOHN ali", convs, nil, time.Now()); len(results) != 1 {
+		t.Errorf("expected case-insensitive multi-term match, got %d", len(results))
+	}
+}
+
+func TestSearchPrefersRecent(t *testing.T) {
+	now := time.Now()
+	convs := []linkedin.DisplayConversation{{ID: "c1", Title: "Recruiter"}}
+	messages := map[string][]linkedin.DisplayMessage{
+		"c1": {
+			{ID: "old", Body: "the offer letter", Timestamp: now.Add(-30 * 24 * time.Hour)},
+			{ID: "new", Body: "the offer letter", Timestamp: now.Add(-time.Hour)},
+		},
+	}
+
+	results := Search("offer", convs, messages, now)
+	if len(results) != 2 || results[0].MessageID != "new" {
+		t.Errorf("expected the newer message first, got %+v", results)
+	}
+}
+
+func TestTermQuality(t *testing.T) {
+	tests := []struct {
+		text, term string
+		want       int
+	}{
+		{"alice johnson", "ali", matchPrefix},
+		{"alice johnson", "john", matchWordStart},
+		{"alice johnson", "ohn", matchSubstring},
+		{"alice johnson", "bob", matchNone},
+	}
+	for _, tt := range tests {
+		if got := termQuality(tt.text, tt.term); got != tt.want {
+			t.Errorf("termQuality(%q, %q) = %d, want %d", tt.text, tt.term, got, tt.want)
+		}
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	body := "This is a fairly long message body that mentions the keyword somewhere near the middle of it all"
+	got := snippet(body, "keyword")
+	if got[:len("…")] != "…" {
+		t.Errorf("expected leading ellipsis for a trimmed snippet, got %q", got)
+	}
+	if len([]rune(got)) > 2*snippetRadius+len("keyword")+2 {
+		t.Errorf("snippet too long: %q", got)
+	}
+}
+
+func TestRanges(t *testing.T) {
+	got := Ranges("Alice and ALICE", []string{"alice", "and"})
+	want := [][2]int{{0, 5}, {6, 9}, {10, 15}}
+	if len(got) != len(want) {
+		t.Fatalf("Ranges() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Ranges()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestFuzzy(t *testing.T) {
+	if Fuzzy("jsmi", "John Smith") == 0 {
+		t.Error("expected skipped characters to match")
+	}
+	if Fuzzy("smith john", "John Smith") == 0 {
+		t.Error("expected terms to match in any order")
+	}
+	for _, query := range []string{"", "jx", "htimsj", "john carol"} {
+		if got := Fuzzy(query, "John Smith"); got != 0 {
+			t.Errorf("Fuzzy(%q) = %d, expected no match", query, got)
+		}
+	}
+
+	// Adjacent characters and word starts beat scattered ones
+	if Fuzzy("mark", "Mark read") <= Fuzzy("mark", "Mute conversation (kind of) read") {
+		t.Error("expected a whole word to outrank scattered characters")
+	}
+	if Fuzzy("ex", "Export conversation") <= Fuzzy("ex", "Next panel") {
+		t.Error("expected a word start to outrank a match inside a word")
+	}
+}
@@ -30,11 +30,11 @@ func New(s styles.Styles) Model {
 	ta.BlurredStyle.CursorLine = lipgloss.NewStyle()
 
 	// Use teal cursor
-	ta.Cursor.Style = lipgloss.NewStyle().Foreground(s.Theme.OwnSender)
+	ta.Cursor.Style = s.ComposeCursor
 
 	// Muted placeholder
-	ta.FocusedStyle.Placeholder = lipgloss.NewStyle().Foreground(s.Theme.Subtle)
-	ta.BlurredStyle.Placeholder = lipgloss.NewStyle().Foreground(s.Theme.Subtle)
+	ta.FocusedStyle.Placeholder = s.Muted
+	ta.BlurredStyle.Placeholder = s.Muted
 
 	return Model{
 		styles:   s,
@@ -83,6 +83,9 @@ func (m *Model) SetRecipient(name string) {
 // SetStyles updates the styles.
 func (m *Model) SetStyles(s styles.Styles) {
 	m.styles = s
+	m.textarea.Cursor.Style = s.ComposeCursor
+	m.textarea.FocusedStyle.Placeholder = s.Muted
+	m.textarea.BlurredStyle.Placeholder = s.Muted
 }
 
 // Value returns the current text content.
@@ -3,6 +3,7 @@ package convlist
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/ggfevans/endorse/internal/ui/styles"
@@ -14,9 +15,10 @@ type Conversation struct {
 	ID          string
 	Name        string
 	LastMessage string
-	Timestamp   string
+	Timestamp   time.Time
 	Unread      bool
 	UnreadCount int
+	MessageID   string // search hit to jump to when opened ("" = none)
 }
 
 // Model represents the conversation list panel.
@@ -33,6 +35,18 @@ type Model struct {
 	filterTab   int // 0=Inbox, 1=Unread
 	inboxCount  int
 	unreadCount int
+	folderName  string // other folder shown instead of the tabs ("" = tabs)
+	folderCount int
+
+	// History paging footer
+	loadingMore bool // older page request in flight
+	hasMore     bool // server reported more history
+
+	// Search mode
+	searching bool
+	query     string
+
+	absolute bool // show clock times instead of relative ones
 }
 
 // New creates a new conversation list model.
@@ -46,6 +60,11 @@ func (m *Model) SetSize(w, h int) {
 	m.height = h
 }
 
+// SetAbsoluteTimes switches between relative ("5m ago") and clock timestamps.
+func (m *Model) SetAbsoluteTimes(absolute bool) {
+	m.absolute = absolute
+}
+
 // Focus gives focus.
 func (m *Model) Focus() { m.focused = true }
 
@@ -74,6 +93,18 @@ func (m *Model) SetConversations(convs []Conversation) {
 	}
 }
 
+// SelectID moves the selection to the conversation with the given ID.
+// It returns false and leaves the selection unchanged if it isn't listed.
+func (m *Model) SelectID(id string) bool {
+	i := m.ConversationIndex(id)
+	if i < 0 {
+		return false
+	}
+	m.selected = i
+	m.ensureVisible()
+	return true
+}
+
 // SetStyles updates the styles.
 func (m *Model) SetStyles(s styles.Styles) {
 	m.styles = s
@@ -89,12 +120,73 @@ func (m *Model) ToggleFilter() {
 	m.offset = 0
 }
 
+// SetFilterTab selects the Inbox (0) or Unread (1) tab and resets the
+// selection, as ToggleFilter does.
+func (m *Model) SetFilterTab(tab int) {
+	m.filterTab = tab
+	m.selected = 0
+	m.offset = 0
+}
+
+// SetFolder shows a folder other than Inbox or Unread in place of the filter
+// tabs. An empty name restores the tabs.
+func (m *Model) SetFolder(name string, count int) {
+	m.folderName = name
+	m.folderCount = count
+}
+
 // SetFilterCounts updates the tab counts.
 func (m *Model) SetFilterCounts(inbox, unread int) {
 	m.inboxCount = inbox
 	m.unreadCount = unread
 }
 
+// StartSearch enters search mode with an empty query.
+func (m *Model) StartSearch() {
+	m.searching = true
+	m.query = ""
+	m.selected = 0
+	m.offset = 0
+}
+
+// StopSearch leaves search mode.
+func (m *Model) StopSearch() {
+	m.searching = false
+	m.query = ""
+	m.selected = 0
+	m.offset = 0
+}
+
+// Searching returns whether search mode is active.
+func (m Model) Searching() bool { return m.searching }
+
+// Query returns the current search query.
+func (m Model) Query() string { return m.query }
+
+// SetQuery updates the search query and resets the selection to the best hit.
+func (m *Model) SetQuery(q string) {
+	m.query = q
+	m.selected = 0
+	m.offset = 0
+}
+
+// SetHistory updates the paging footer state.
+func (m *Model) SetHistory(loading, hasMore bool) {
+	m.loadingMore = loading
+	m.hasMore = hasMore
+}
+
+// LoadingMore returns whether an older page is being fetched.
+func (m Model) LoadingMore() bool { return m.loadingMore }
+
+// HasMore returns whether older conversations may exist on the server.
+func (m Model) HasMore() bool { return m.hasMore }
+
+// NearBottom reports whether the selection is within n entries of the end.
+func (m Model) NearBottom(n int) bool {
+	return len(m.conversations) > 0 && m.selected >= len(m.conversations)-1-n
+}
+
 // MoveDown moves selection down.
 func (m *Model) MoveDown() {
 	if m.selected < len(m.conversations)-1 {
@@ -155,19 +247,31 @@ func (m Model) View() string {
 	unreadLabel := fmt.Sprintf("Unread %d", m.unreadCount)
 	sep := m.styles.Muted.Render(" · ")
 
-	tabSelected := lipgloss.NewStyle().Foreground(m.styles.Theme.Secondary).Bold(true)
+	tabSelected := m.styles.ActiveTab
 	tabNormal := m.styles.Muted
 
 	var tabBar string
-	if m.filterTab == 0 {
+	switch {
+	case m.searching:
+		tabBar = tabSelected.Render("/") + " " + util.Truncate(m.query, contentWidth-3) + m.styles.AccentText.Render("▏")
+	case m.folderName != "":
+		tabBar = tabSelected.Render(fmt.Sprintf("%s %d", m.folderName, m.folderCount))
+	case m.filterTab == 0:
 		tabBar = tabSelected.Render(inboxLabel) + sep + tabNormal.Render(unreadLabel)
-	} else {
+	default:
 		tabBar = tabNormal.Render(inboxLabel) + sep + tabSelected.Render(unreadLabel)
 	}
 	content := tabBar + "\n"
 
 	if len(m.conversations) == 0 {
-		content += "\n" + m.styles.Muted.Render("  No conversations")
+		empty := "No conversations"
+		if m.searching {
+			empty = "No matches"
+			if m.query == "" {
+				empty = "Type to search"
+			}
+		}
+		content += "\n" + m.styles.Muted.Render("  "+empty)
 	} else {
 		visible := m.visibleEntries()
 		if visible < 1 {
@@ -178,15 +282,19 @@ func (m Model) View() string {
 			end = len(m.conversations)
 		}
 
-		accentBar := lipgloss.NewStyle().Foreground(m.styles.Theme.Secondary).Render("▎")
+		accentBar := m.styles.SelectionBar.Render("▎")
 
 		for i := m.offset; i < end; i++ {
 			c := m.conversations[i]
 			name := util.Truncate(c.Name, contentWidth-2)
 			preview := util.Truncate(c.LastMessage, contentWidth-2)
-			ts := c.Timestamp
+			ts := util.FormatTime(c.Timestamp, m.absolute)
+			badge := ""
+			if c.UnreadCount > 0 {
+				badge = m.styles.Unread.Render(unreadBadge(c.UnreadCount))
+			}
 
-			nameStyle := lipgloss.NewStyle().Foreground(m.styles.Theme.Foreground)
+			nameStyle := m.styles.Text
 			prefix := "  "
 			if c.Unread && i == m.selected {
 				nameStyle = nameStyle.Bold(true)
@@ -198,13 +306,18 @@ func (m Model) View() string {
 				prefix = m.styles.Unread.Render("● ")
 			}
 
+			right := m.styles.Muted.Render(ts)
+			if badge != "" {
+				right = badge + " " + right
+			}
+
 			line := prefix + nameStyle.Render(name)
-			if ts != "" {
-				gap := contentWidth - lipgloss.Width(prefix) - lipgloss.Width(name) - lipgloss.Width(ts)
+			if badge != "" || ts != "" {
+				gap := contentWidth - lipgloss.Width(prefix) - lipgloss.Width(name) - lipgloss.Width(right)
 				if gap < 1 {
 					gap = 1
 				}
-				line = prefix + nameStyle.Render(name) + strings.Repeat(" ", gap) + m.styles.Muted.Render(ts)
+				line = prefix + nameStyle.Render(name) + strings.Repeat(" ", gap) + right
 			}
 
 			previewLine := "  " + m.styles.Muted.Render(preview)
@@ -214,12 +327,35 @@ func (m Model) View() string {
 	}
 
 	innerHeight := m.height - 2 // subtract top/bottom border
+	if len(m.conversations) > 0 && innerHeight > 1 && !m.searching {
+		content = util.PadToHeight(content, innerHeight-1) + "\n" + m.footerView(contentWidth)
+	}
 	content = util.PadToHeight(content, innerHeight)
 	return border.
 		Width(m.width - 2).
 		Render(content)
 }
 
+// footerView renders the history paging status line.
+func (m Model) footerView(width int) string {
+	text := "No older conversations"
+	switch {
+	case m.loadingMore:
+		text = "Loading older…"
+	case m.hasMore:
+		text = "More history below"
+	}
+	return m.styles.Muted.Render("  " + util.Truncate(text, width-2))
+}
+
+// unreadBadge formats an unread count, capping large counts.
+func unreadBadge(n int) string {
+	if n > 99 {
+		return "99+"
+	}
+	return fmt.Sprintf("(%d)", n)
+}
+
 func (m Model) visibleEntries() int {
 	// borders(2) + title line(1) + gap(1) = 4 lines of overhead
 	visibleLines := m.height - 4
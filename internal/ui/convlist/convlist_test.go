@@ -4,6 +4,7 @@ import (
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ggfevans/endorse/internal/config"
 	"github.com/ggfevans/endorse/internal/ui/styles"
@@ -27,9 +28,9 @@ func newTestConvList() Model {
 
 func sampleConversations() []Conversation {
 	return []Conversation{
-		{ID: "1", Name: "Alice Johnson", LastMessage: "Hey there!", Timestamp: "10:30", Unread: true, UnreadCount: 2},
-		{ID: "2", Name: "Bob Smith", LastMessage: "See you tomorrow", Timestamp: "09:15", Unread: false, UnreadCount: 0},
-		{ID: "3", Name: "Carol White", LastMessage: "Thanks!", Timestamp: "Yesterday", Unread: true, UnreadCount: 1},
+		{ID: "1", Name: "Alice Johnson", LastMessage: "Hey there!", Timestamp: time.Now().Add(-30 * time.Minute), Unread: true, UnreadCount: 2},
+		{ID: "2", Name: "Bob Smith", LastMessage: "See you tomorrow", Timestamp: time.Now().Add(-2 * time.Hour), Unread: false, UnreadCount: 0},
+		{ID: "3", Name: "Carol White", LastMessage: "Thanks!", Timestamp: time.Now().AddDate(0, 0, -1), Unread: true, UnreadCount: 1},
 	}
 }
 
@@ -181,3 +182,130 @@ func TestCountAndUnreadCount(t *testing.T) {
 		t.Errorf("expected UnreadCount()=2, got %d", m.UnreadCount())
 	}
 }
+
+func TestNearBottom(t *testing.T) {
+	m := newTestConvList()
+	m.SetSize(30, 20)
+
+	if m.NearBottom(1) {
+		t.Error("expected NearBottom()=false for empty list")
+	}
+
+	m.SetConversations(sampleConversations())
+	if m.NearBottom(1) {
+		t.Error("expected NearBottom(1)=false at first of three")
+	}
+	m.MoveDown()
+	if !m.NearBottom(1) {
+		t.Error("expected NearBottom(1)=true one away from the end")
+	}
+}
+
+func TestHistoryFooter(t *testing.T) {
+	m := newTestConvList()
+	m.SetSize(30, 20)
+	m.SetConversations(sampleConversations())
+
+	tests := []struct {
+		loading, hasMore bool
+		want             string
+	}{
+		{loading: true, hasMore: true, want: "Loading older"},
+		{loading: false, hasMore: true, want: "More history"},
+		{loading: false, hasMore: false, want: "No older conversations"},
+	}
+	for _, tt := range tests {
+		m.SetHistory(tt.loading, tt.hasMore)
+		output := m.View()
+		if countRenderedLines(output) != 20 {
+			t.Errorf("expected 20 lines with footer, got %d", countRenderedLines(output))
+		}
+		if !strings.Contains(stripAnsi(output), tt.want) {
+			t.Errorf("expected footer %q, got:\n%s", tt.want, stripAnsi(output))
+		}
+	}
+}
+
+func TestSearchMode(t *testing.T) {
+	m := newTestConvList()
+	m.SetSize(30, 20)
+	m.SetConversations(sampleConversations())
+	m.MoveDown()
+
+	m.StartSearch()
+	if !m.Searching() {
+		t.Fatal("expected Searching()=true after StartSearch")
+	}
+	if m.Selected() != 0 {
+		t.Errorf("expected selection reset to 0 on StartSearch, got %d", m.Selected())
+	}
+
+	m.SetConversations(nil)
+	output := stripAnsi(m.View())
+	if !strings.Contains(output, "Type to search") {
+		t.Errorf("expected search prompt for empty query, got:\n%s", output)
+	}
+
+	m.SetQuery("zzz")
+	output = stripAnsi(m.View())
+	if !strings.Contains(output, "/ zzz") {
+		t.Errorf("expected query in search bar, got:\n%s", output)
+	}
+	if !strings.Contains(output, "No matches") {
+		t.Errorf("expected 'No matches' for empty results, got:\n%s", output)
+	}
+
+	m.StopSearch()
+	if m.Searching() || m.Query() != "" {
+		t.Error("expected search state cleared after StopSearch")
+	}
+}
+
+func TestUnreadBadge(t *testing.T) {
+	m := newTestConvList()
+	m.SetSize(40, 20)
+	m.SetConversations(sampleConversations())
+
+	output := stripAnsi(m.View())
+	if !strings.Contains(output, "(2)") || !strings.Contains(output, "(1)") {
+		t.Errorf("expected unread badges, got:\n%s", output)
+	}
+	if unreadBadge(150) != "99+" {
+		t.Errorf("expected large counts capped, got %q", unreadBadge(150))
+	}
+}
+
+func TestFolderLabel(t *testing.T) {
+	m := newTestConvList()
+	m.SetSize(40, 20)
+	m.SetFilterCounts(3, 2)
+
+	m.SetFolder("Pinned", 1)
+	output := stripAnsi(m.View())
+	if !strings.Contains(output, "Pinned 1") || strings.Contains(output, "Inbox 3") {
+		t.Errorf("expected folder label in place of tabs, got:\n%s", output)
+	}
+
+	m.SetFolder("", 0)
+	m.SetFilterTab(1)
+	output = stripAnsi(m.View())
+	if !strings.Contains(output, "Inbox 3") || m.FilterTab() != 1 {
+		t.Errorf("expected tabs restored with Unread selected, got tab %d:\n%s", m.FilterTab(), output)
+	}
+}
+
+func TestTimestampToggle(t *testing.T) {
+	m := newTestConvList()
+	m.SetSize(40, 20)
+	m.SetConversations(sampleConversations())
+
+	if out := stripAnsi(m.View()); !strings.Contains(out, "30m ago") {
+		t.Errorf("expected relative timestamps by default, got:\n%s", out)
+	}
+
+	m.SetAbsoluteTimes(true)
+	want := sampleConversations()[0].Timestamp.Format("3:04 PM")
+	if out := stripAnsi(m.View()); strings.Contains(out, "30m ago") || !strings.Contains(out, want) {
+		t.Errorf("expected absolute timestamp %q, got:\n%s", want, out)
+	}
+}
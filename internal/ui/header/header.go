@@ -1,6 +1,8 @@
 package header
 
 import (
+	"fmt"
+
 	"github.com/charmbracelet/lipgloss"
 	"github.com/ggfevans/endorse/internal/ui/styles"
 )
@@ -10,7 +12,9 @@ type Model struct {
 	styles    styles.Styles
 	width     int
 	username  string
+	profile   string
 	connected bool
+	unread    int
 }
 
 // New creates a new header model.
@@ -32,11 +36,22 @@ func (m *Model) SetUsername(u string) {
 	m.username = u
 }
 
+// SetProfile sets the profile name shown beside the username. Leave it
+// empty when there is only the default profile.
+func (m *Model) SetProfile(p string) {
+	m.profile = p
+}
+
 // SetConnected sets the connection status.
 func (m *Model) SetConnected(c bool) {
 	m.connected = c
 }
 
+// SetUnread sets the total number of unread messages.
+func (m *Model) SetUnread(n int) {
+	m.unread = n
+}
+
 // SetStyles updates the styles.
 func (m *Model) SetStyles(s styles.Styles) {
 	m.styles = s
@@ -53,6 +68,14 @@ func (m Model) View() string {
 		parts = append(parts, m.styles.Muted.Render("@"+m.username))
 	}
 
+	if m.profile != "" {
+		parts = append(parts, m.styles.Muted.Render("["+m.profile+"]"))
+	}
+
+	if m.unread > 0 {
+		parts = append(parts, m.styles.Unread.Render(fmt.Sprintf("● %d unread", m.unread)))
+	}
+
 	if m.connected {
 		parts = append(parts, m.styles.Connected.Render("★"))
 	} else {
@@ -83,3 +83,29 @@ func TestWidth(t *testing.T) {
 		t.Errorf("expected first line width >= 60 (for width=80), got %d", runeWidth)
 	}
 }
+
+func TestUnreadTotal(t *testing.T) {
+	m := newTestHeader()
+	m.SetWidth(80)
+
+	if output := stripAnsi(m.View()); strings.Contains(output, "unread") {
+		t.Errorf("expected no unread total when zero, got:\n%s", output)
+	}
+
+	m.SetUnread(5)
+	if output := stripAnsi(m.View()); !strings.Contains(output, "5 unread") {
+		t.Errorf("expected unread total in header, got:\n%s", output)
+	}
+}
+
+func TestProfileShown(t *testing.T) {
+	m := newTestHeader()
+	m.SetWidth(80)
+	m.SetUsername("ggfevans")
+	m.SetProfile("work")
+
+	output := stripAnsi(m.View())
+	if !strings.Contains(output, "@ggfevans  [work]") {
+		t.Errorf("expected profile after username, got:\n%s", output)
+	}
+}
@@ -32,7 +32,7 @@ const (
 	headerHeight    = 1
 	statusBarHeight = 1
 	minSidebarWidth = 12
-	sidebarWidth    = 14
+	sidebarWidth    = 18
 )
 
 // Calculate computes panel dimensions from terminal size.
@@ -44,6 +44,14 @@ func Calculate(width, height int) Dimensions {
 	}
 
 	switch {
+	case width >= 90:
+		d.Mode = ThreePanel
+		d.SidebarWidth = sidebarWidth
+		d.ConvListWidth = (width - d.SidebarWidth) * 30 / 100
+		if d.ConvListWidth < 24 {
+			d.ConvListWidth = 24
+		}
+		d.ThreadWidth = width - d.SidebarWidth - d.ConvListWidth
 	case width >= 60:
 		d.Mode = TwoPanel
 		d.SidebarWidth = 0
@@ -62,6 +70,16 @@ func Calculate(width, height int) Dimensions {
 	return d
 }
 
+// ContentTop is the screen row the panels start on, below the header.
+func (d Dimensions) ContentTop() int {
+	return headerHeight
+}
+
+// ThreadLeft is the screen column the thread panel starts on.
+func (d Dimensions) ThreadLeft() int {
+	return d.SidebarWidth + d.ConvListWidth
+}
+
 func max(a, b int) int {
 	if a > b {
 		return a
@@ -2,22 +2,22 @@ package layout
 
 import "testing"
 
-func TestTwoPanelWide(t *testing.T) {
+func TestThreePanelWide(t *testing.T) {
 	d := Calculate(120, 40)
 
-	if d.Mode != TwoPanel {
-		t.Errorf("expected TwoPanel mode, got %d", d.Mode)
+	if d.Mode != ThreePanel {
+		t.Errorf("expected ThreePanel mode, got %d", d.Mode)
 	}
-	if d.SidebarWidth != 0 {
-		t.Errorf("expected SidebarWidth=0, got %d", d.SidebarWidth)
+	if d.SidebarWidth < minSidebarWidth {
+		t.Errorf("expected SidebarWidth>=%d, got %d", minSidebarWidth, d.SidebarWidth)
 	}
 	if d.ContentHeight != 38 {
 		t.Errorf("expected ContentHeight=38, got %d", d.ContentHeight)
 	}
-	if d.ConvListWidth+d.ThreadWidth != 120 {
-		t.Errorf("expected widths to sum to 120, got %d+%d=%d",
-			d.ConvListWidth, d.ThreadWidth,
-			d.ConvListWidth+d.ThreadWidth)
+	if d.SidebarWidth+d.ConvListWidth+d.ThreadWidth != 120 {
+		t.Errorf("expected widths to sum to 120, got %d+%d+%d=%d",
+			d.SidebarWidth, d.ConvListWidth, d.ThreadWidth,
+			d.SidebarWidth+d.ConvListWidth+d.ThreadWidth)
 	}
 }
 
@@ -58,6 +58,9 @@ func TestSinglePanelMode(t *testing.T) {
 	if d.ContentHeight != 18 {
 		t.Errorf("expected ContentHeight=18, got %d", d.ContentHeight)
 	}
+	if d.ThreadLeft() != 0 || d.ContentTop() != 1 {
+		t.Errorf("expected the thread at column 0 below the header, got %d, %d", d.ThreadLeft(), d.ContentTop())
+	}
 }
 
 func TestContentHeightAllModes(t *testing.T) {
@@ -66,7 +69,7 @@ func TestContentHeightAllModes(t *testing.T) {
 		width  int
 		height int
 	}{
-		{"TwoPanelWide", 120, 40},
+		{"ThreePanel", 120, 40},
 		{"TwoPanelNarrow", 70, 30},
 		{"SinglePanel", 50, 20},
 	}
@@ -96,7 +99,8 @@ func TestBoundaryModeTransitions(t *testing.T) {
 		width    int
 		expected LayoutMode
 	}{
-		{"width=90 is TwoPanel", 90, TwoPanel},
+		{"width=90 is ThreePanel", 90, ThreePanel},
+		{"width=89 is TwoPanel", 89, TwoPanel},
 		{"width=60 is TwoPanel", 60, TwoPanel},
 		{"width=59 is SinglePanel", 59, SinglePanel},
 	}
@@ -112,7 +116,7 @@ func TestBoundaryModeTransitions(t *testing.T) {
 }
 
 func TestWidthSumsTwoPanel(t *testing.T) {
-	widths := []int{60, 70, 80, 90, 100, 120, 200}
+	widths := []int{60, 70, 80, 89}
 	for _, w := range widths {
 		d := Calculate(w, 30)
 		if d.Mode != TwoPanel {
@@ -125,3 +129,21 @@ func TestWidthSumsTwoPanel(t *testing.T) {
 		}
 	}
 }
+
+func TestWidthSumsThreePanel(t *testing.T) {
+	widths := []int{90, 100, 120, 200}
+	for _, w := range widths {
+		d := Calculate(w, 30)
+		if d.Mode != ThreePanel {
+			t.Fatalf("expected ThreePanel for width=%d, got %d", w, d.Mode)
+		}
+		total := d.SidebarWidth + d.ConvListWidth + d.ThreadWidth
+		if total != w {
+			t.Errorf("ThreePanel width=%d: SidebarWidth(%d)+ConvListWidth(%d)+ThreadWidth(%d)=%d, want %d",
+				w, d.SidebarWidth, d.ConvListWidth, d.ThreadWidth, total, w)
+		}
+		if d.ThreadWidth < d.ConvListWidth {
+			t.Errorf("ThreePanel width=%d: expected thread to be the widest panel, got %d < %d", w, d.ThreadWidth, d.ConvListWidth)
+		}
+	}
+}
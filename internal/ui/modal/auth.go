@@ -10,25 +10,59 @@ import (
 )
 
 // AuthSubmitMsg is sent when the user submits auth credentials.
+// Passphrase is set when the form asked for one to encrypt them with.
 type AuthSubmitMsg struct {
 	Cookie       string
 	PageInstance string
 	XLiTrack     string
+	Passphrase   string
 }
 
+// AuthImportMsg is sent when the user asks to sign in from a cookies.txt
+// or HAR file instead of a pasted cookie.
+type AuthImportMsg struct {
+	Path       string
+	Passphrase string
+}
+
+// PassphraseSubmitMsg is sent when the user enters a passphrase on its own,
+// to unlock or encrypt credentials already saved.
+type PassphraseSubmitMsg struct {
+	Passphrase string
+}
+
+// PassphraseMode controls which passphrase fields the auth form shows.
+type PassphraseMode int
+
+const (
+	// PassphraseOff asks for the cookie only.
+	PassphraseOff PassphraseMode = iota
+	// PassphraseChoose asks for the cookie and a new passphrase to encrypt it.
+	PassphraseChoose
+	// PassphraseUnlock asks only for the passphrase of saved credentials.
+	PassphraseUnlock
+	// PassphraseMigrate asks only for a new passphrase, to encrypt
+	// credentials saved in plaintext.
+	PassphraseMigrate
+)
+
 // AuthModel is the auth form modal.
 type AuthModel struct {
 	styles     styles.Styles
 	width      int
 	height     int
 	inputs     []textinput.Model
-	focusIndex int
+	focusIndex int // index into visible()
+	mode       PassphraseMode
 	err        string
 	loading    bool
 }
 
 const (
 	authInputCookie = iota
+	authInputImport
+	authInputPassphrase
+	authInputConfirm
 	authInputCount
 )
 
@@ -42,6 +76,21 @@ func NewAuth(s styles.Styles) AuthModel {
 	inputs[authInputCookie].CharLimit = 0 // unlimited
 	inputs[authInputCookie].Width = 60
 
+	inputs[authInputImport] = textinput.New()
+	inputs[authInputImport].Placeholder = "~/Downloads/www.linkedin.com.har"
+	inputs[authInputImport].CharLimit = 0
+	inputs[authInputImport].Width = 60
+
+	for _, i := range []int{authInputPassphrase, authInputConfirm} {
+		inputs[i] = textinput.New()
+		inputs[i].EchoMode = textinput.EchoPassword
+		inputs[i].EchoCharacter = '•'
+		inputs[i].CharLimit = 0
+		inputs[i].Width = 60
+	}
+	inputs[authInputPassphrase].Placeholder = "Passphrase"
+	inputs[authInputConfirm].Placeholder = "Passphrase again"
+
 	return AuthModel{
 		styles: s,
 		inputs: inputs,
@@ -64,6 +113,35 @@ func (m *AuthModel) SetSize(w, h int) {
 	}
 }
 
+// SetPassphraseMode switches which passphrase fields are shown, clearing
+// any passphrase typed so far.
+func (m *AuthModel) SetPassphraseMode(mode PassphraseMode) {
+	m.mode = mode
+	m.inputs[authInputPassphrase].Reset()
+	m.inputs[authInputConfirm].Reset()
+	m.focusIndex = 0
+	m.updateFocus()
+}
+
+// PassphraseMode returns which passphrase fields are shown.
+func (m AuthModel) PassphraseMode() PassphraseMode {
+	return m.mode
+}
+
+// visible returns the inputs shown in the current mode, in tab order.
+func (m AuthModel) visible() []int {
+	switch m.mode {
+	case PassphraseChoose:
+		return []int{authInputCookie, authInputImport, authInputPassphrase, authInputConfirm}
+	case PassphraseUnlock:
+		return []int{authInputPassphrase}
+	case PassphraseMigrate:
+		return []int{authInputPassphrase, authInputConfirm}
+	default:
+		return []int{authInputCookie, authInputImport}
+	}
+}
+
 // SetError sets an error message.
 func (m *AuthModel) SetError(err string) {
 	m.err = err
@@ -86,41 +164,75 @@ func (m AuthModel) Update(msg tea.Msg) (AuthModel, tea.Cmd) {
 		return m, nil
 	}
 
+	visible := m.visible()
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "tab", "down":
-			m.focusIndex = (m.focusIndex + 1) % authInputCount
+			m.focusIndex = (m.focusIndex + 1) % len(visible)
 			return m, m.updateFocus()
 		case "shift+tab", "up":
-			m.focusIndex = (m.focusIndex - 1 + authInputCount) % authInputCount
+			m.focusIndex = (m.focusIndex - 1 + len(visible)) % len(visible)
 			return m, m.updateFocus()
 		case "enter":
-			cookie := strings.TrimSpace(m.inputs[authInputCookie].Value())
-			if cookie == "" {
-				m.err = "Cookie header is required"
-				return m, nil
-			}
-			m.err = ""
-			m.loading = true
-			return m, func() tea.Msg {
-				return AuthSubmitMsg{
-					Cookie: cookie,
-				}
-			}
+			return m.submit()
 		}
 	}
 
 	// Update the focused input
 	var cmd tea.Cmd
-	m.inputs[m.focusIndex], cmd = m.inputs[m.focusIndex].Update(msg)
+	i := visible[m.focusIndex]
+	m.inputs[i], cmd = m.inputs[i].Update(msg)
 	return m, cmd
 }
 
+// submit checks the visible fields and sends them on.
+func (m AuthModel) submit() (AuthModel, tea.Cmd) {
+	cookie := strings.TrimSpace(m.inputs[authInputCookie].Value())
+	path := strings.TrimSpace(m.inputs[authInputImport].Value())
+	pass := m.inputs[authInputPassphrase].Value()
+
+	switch {
+	case (m.mode == PassphraseOff || m.mode == PassphraseChoose) && cookie == "" && path == "":
+		m.err = "Cookie header or a file to import is required"
+		return m, nil
+	case m.mode != PassphraseOff && pass == "":
+		m.err = "Passphrase is required"
+		return m, nil
+	case (m.mode == PassphraseChoose || m.mode == PassphraseMigrate) && pass != m.inputs[authInputConfirm].Value():
+		m.err = "Passphrases don't match"
+		return m, nil
+	}
+
+	m.err = ""
+	m.loading = true
+	if m.mode == PassphraseUnlock || m.mode == PassphraseMigrate {
+		return m, func() tea.Msg {
+			return PassphraseSubmitMsg{Passphrase: pass}
+		}
+	}
+	if m.mode == PassphraseOff {
+		pass = ""
+	}
+	// A pasted cookie wins over a file named as well
+	if cookie == "" {
+		return m, func() tea.Msg {
+			return AuthImportMsg{Path: path, Passphrase: pass}
+		}
+	}
+	return m, func() tea.Msg {
+		return AuthSubmitMsg{
+			Cookie:     cookie,
+			Passphrase: pass,
+		}
+	}
+}
+
 func (m AuthModel) updateFocus() tea.Cmd {
+	focused := m.visible()[m.focusIndex]
 	cmds := make([]tea.Cmd, authInputCount)
 	for i := range m.inputs {
-		if i == m.focusIndex {
+		if i == focused {
 			cmds[i] = m.inputs[i].Focus()
 		} else {
 			m.inputs[i].Blur()
@@ -133,8 +245,28 @@ func (m AuthModel) updateFocus() tea.Cmd {
 func (m AuthModel) View() string {
 	titleStyle := m.styles.AccentText.Align(lipgloss.Center)
 	mutedStyle := m.styles.Muted
+	labelStyle := m.styles.AccentText
 
 	var b strings.Builder
+	switch m.mode {
+	case PassphraseUnlock:
+		b.WriteString(titleStyle.Render("Unlock Saved Session"))
+		b.WriteString("\n\n")
+		b.WriteString(mutedStyle.Render("Your LinkedIn session is stored encrypted."))
+		b.WriteString("\n\n")
+		m.writePassphraseFields(&b, labelStyle)
+		return m.box(b.String(), "Press Enter to unlock")
+	case PassphraseMigrate:
+		b.WriteString(titleStyle.Render("Encrypt Saved Session"))
+		b.WriteString("\n\n")
+		b.WriteString(mutedStyle.Render("Your LinkedIn session is saved unencrypted. Choose a"))
+		b.WriteString("\n")
+		b.WriteString(mutedStyle.Render("passphrase to encrypt it; you'll need it on each launch."))
+		b.WriteString("\n\n")
+		m.writePassphraseFields(&b, labelStyle)
+		return m.box(b.String(), "Press Enter to encrypt")
+	}
+
 	b.WriteString(titleStyle.Render("Endorse Authentication"))
 	b.WriteString("\n\n")
 	b.WriteString(mutedStyle.Render("Extract cookies from your browser DevTools:"))
@@ -148,14 +280,48 @@ func (m AuthModel) View() string {
 	b.WriteString(mutedStyle.Render("   (Or: paste full header, or just the li_at=... value)"))
 	b.WriteString("\n\n")
 
-	labelStyle := lipgloss.NewStyle().Foreground(m.styles.Theme.Accent).Bold(true)
 	b.WriteString(labelStyle.Render("Cookie Header:"))
 	b.WriteString("\n")
 	b.WriteString(m.inputs[authInputCookie].View())
 	b.WriteString("\n\n")
 
+	b.WriteString(labelStyle.Render("Or Import From File:"))
+	b.WriteString(mutedStyle.Render(" cookies.txt or HAR export"))
+	b.WriteString("\n")
+	b.WriteString(m.inputs[authInputImport].View())
+	b.WriteString("\n\n")
+
+	if m.mode == PassphraseChoose {
+		b.WriteString(mutedStyle.Render("Choose a passphrase to encrypt the saved session:"))
+		b.WriteString("\n")
+		m.writePassphraseFields(&b, labelStyle)
+	}
+
+	return m.box(b.String(), "Press Enter to authenticate")
+}
+
+// writePassphraseFields renders the passphrase input, and its confirmation
+// when choosing a new one.
+func (m AuthModel) writePassphraseFields(b *strings.Builder, labelStyle lipgloss.Style) {
+	b.WriteString(labelStyle.Render("Passphrase:"))
+	b.WriteString("\n")
+	b.WriteString(m.inputs[authInputPassphrase].View())
+	b.WriteString("\n\n")
+	if m.mode != PassphraseUnlock {
+		b.WriteString(labelStyle.Render("Confirm Passphrase:"))
+		b.WriteString("\n")
+		b.WriteString(m.inputs[authInputConfirm].View())
+		b.WriteString("\n\n")
+	}
+}
+
+// box adds the error and prompt to the form and centers it in a border.
+func (m AuthModel) box(form, prompt string) string {
+	var b strings.Builder
+	b.WriteString(form)
+
 	if m.err != "" {
-		errStyle := lipgloss.NewStyle().Foreground(m.styles.Theme.Error)
+		errStyle := m.styles.ErrorText
 		b.WriteString(errStyle.Render("Error: " + m.err))
 		b.WriteString("\n\n")
 	}
@@ -163,7 +329,7 @@ func (m AuthModel) View() string {
 	if m.loading {
 		b.WriteString(m.styles.AccentText.Render("Validating credentials..."))
 	} else {
-		b.WriteString(mutedStyle.Render("Press Enter to authenticate"))
+		b.WriteString(m.styles.Muted.Render(prompt))
 	}
 
 	contentWidth := m.width - 8
@@ -174,11 +340,7 @@ func (m AuthModel) View() string {
 		contentWidth = 90
 	}
 
-	boxStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(m.styles.Theme.Accent).
-		Padding(1, 3).
-		Width(contentWidth)
+	boxStyle := m.styles.Modal.Width(contentWidth)
 
 	box := boxStyle.Render(b.String())
 
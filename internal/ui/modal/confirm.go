@@ -14,11 +14,20 @@ type ConfirmModel struct {
 	height  int
 	message string
 	active  bool
+
+	confirmKey string
+	cancelKey  string
 }
 
 // NewConfirm creates a new confirmation modal.
 func NewConfirm(s styles.Styles) ConfirmModel {
-	return ConfirmModel{styles: s}
+	return ConfirmModel{styles: s, confirmKey: "Enter", cancelKey: "Esc"}
+}
+
+// SetKeys sets the keys named in the hint for confirming and cancelling.
+func (m *ConfirmModel) SetKeys(confirm, cancel string) {
+	m.confirmKey = confirm
+	m.cancelKey = cancel
 }
 
 // Show displays the modal with the given message.
@@ -44,6 +53,11 @@ func (m *ConfirmModel) SetSize(w, h int) {
 	m.height = h
 }
 
+// SetStyles updates styles.
+func (m *ConfirmModel) SetStyles(s styles.Styles) {
+	m.styles = s
+}
+
 // View renders the confirmation modal centered on screen.
 func (m ConfirmModel) View() string {
 	if !m.active {
@@ -51,8 +65,8 @@ func (m ConfirmModel) View() string {
 	}
 
 	title := m.styles.AccentText.Render("Confirm Delete")
-	msg := lipgloss.NewStyle().Foreground(m.styles.Theme.Foreground).Render(m.message)
-	hint := m.styles.Muted.Render("Enter to confirm  |  Esc to cancel")
+	msg := m.styles.Text.Render(m.message)
+	hint := m.styles.Muted.Render(m.confirmKey + " to confirm  |  " + m.cancelKey + " to cancel")
 
 	content := fmt.Sprintf("%s\n\n%s\n\n%s", title, msg, hint)
 
@@ -64,11 +78,7 @@ func (m ConfirmModel) View() string {
 		boxWidth = 30
 	}
 
-	boxStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(m.styles.Theme.Error).
-		Padding(1, 3).
-		Width(boxWidth)
+	boxStyle := m.styles.ModalDanger.Width(boxWidth)
 
 	box := boxStyle.Render(content)
 
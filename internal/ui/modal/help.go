@@ -0,0 +1,213 @@
+package modal
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ggfevans/endorse/internal/ui/styles"
+	"github.com/ggfevans/endorse/internal/util"
+)
+
+// HelpSection is a group of key bindings, one per place keys are listened
+// for.
+type HelpSection struct {
+	Title   string
+	Entries []HelpEntry
+}
+
+// HelpEntry is one action: its keys, already formatted, and what it does.
+type HelpEntry struct {
+	Keys string
+	Desc string
+}
+
+// HelpModel lists every key binding, with the section for the focused panel
+// first. It scrolls when the list is taller than the screen.
+type HelpModel struct {
+	styles   styles.Styles
+	width    int
+	height   int
+	sections []HelpSection
+	current  string
+	offset   int
+	active   bool
+}
+
+// NewHelp creates a new help screen.
+func NewHelp(s styles.Styles) HelpModel {
+	return HelpModel{styles: s}
+}
+
+// Show opens the help screen. current is the title of the section for the
+// focused panel.
+func (m *HelpModel) Show(sections []HelpSection, current string) {
+	m.sections = sections
+	m.active = true
+	m.SetCurrent(current)
+}
+
+// SetCurrent moves another section to the top, for when focus changes
+// while the help screen is open.
+func (m *HelpModel) SetCurrent(current string) {
+	m.current = current
+	m.offset = 0
+}
+
+// Hide dismisses the help screen.
+func (m *HelpModel) Hide() {
+	m.active = false
+}
+
+// Active returns whether the help screen is showing.
+func (m HelpModel) Active() bool {
+	return m.active
+}
+
+// SetSize updates the modal dimensions.
+func (m *HelpModel) SetSize(w, h int) {
+	m.width = w
+	m.height = h
+	m.clampOffset()
+}
+
+// SetStyles updates styles.
+func (m *HelpModel) SetStyles(s styles.Styles) {
+	m.styles = s
+}
+
+// Update handles tea messages. Esc, ? and q close the help screen.
+func (m HelpModel) Update(msg tea.Msg) (HelpModel, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !m.active || !ok {
+		return m, nil
+	}
+
+	page := max(m.visibleLines()-1, 1)
+	switch key.String() {
+	case "esc", "?", "q":
+		m.Hide()
+	case "j", "down":
+		m.offset++
+	case "k", "up":
+		m.offset--
+	case "ctrl+d", "pgdown", " ":
+		m.offset += page
+	case "ctrl+u", "pgup":
+		m.offset -= page
+	case "g", "home":
+		m.offset = 0
+	case "G", "end":
+		m.offset = len(m.lines())
+	}
+	m.clampOffset()
+	return m, nil
+}
+
+// ordered returns the sections with the current one first.
+func (m HelpModel) ordered() []HelpSection {
+	sections := make([]HelpSection, 0, len(m.sections))
+	for _, s := range m.sections {
+		if s.Title == m.current {
+			sections = append(sections, s)
+		}
+	}
+	for _, s := range m.sections {
+		if s.Title != m.current {
+			sections = append(sections, s)
+		}
+	}
+	return sections
+}
+
+// boxWidth is the width of the box, borders included.
+func (m HelpModel) boxWidth() int {
+	w := 72
+	if m.width > 0 && m.width < w+4 {
+		w = m.width - 4
+	}
+	return max(w, 30)
+}
+
+// lines renders the sections, one line per entry so scrolling is by line.
+func (m HelpModel) lines() []string {
+	keyWidth := 0
+	for _, s := range m.sections {
+		for _, e := range s.Entries {
+			keyWidth = max(keyWidth, lipgloss.Width(e.Keys))
+		}
+	}
+	// Borders and padding take 8 columns; leave room for the description
+	inner := m.boxWidth() - 8
+	keyWidth = min(keyWidth, inner/2)
+
+	keyStyle := m.styles.AccentText.Width(keyWidth)
+	descStyle := m.styles.Text
+
+	var lines []string
+	for i, s := range m.ordered() {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		title := m.styles.AccentText.Bold(true).Render(s.Title)
+		if s.Title == m.current {
+			title += m.styles.Muted.Render(" (current)")
+		}
+		lines = append(lines, title)
+		for _, e := range s.Entries {
+			keys := keyStyle.Render(util.Truncate(e.Keys, keyWidth))
+			desc := descStyle.Render(util.Truncate(e.Desc, inner-keyWidth-4))
+			lines = append(lines, "  "+keys+"  "+desc)
+		}
+	}
+	return lines
+}
+
+// visibleLines is how many lines of bindings fit on screen: the box's
+// borders, padding, title and footer take 8.
+func (m HelpModel) visibleLines() int {
+	if m.height == 0 {
+		return len(m.lines())
+	}
+	return max(m.height-8, 1)
+}
+
+func (m *HelpModel) clampOffset() {
+	m.offset = min(m.offset, len(m.lines())-m.visibleLines())
+	m.offset = max(m.offset, 0)
+}
+
+// View renders the help screen centered on screen.
+func (m HelpModel) View() string {
+	if !m.active {
+		return ""
+	}
+
+	lines := m.lines()
+	visible := m.visibleLines()
+	end := min(m.offset+visible, len(lines))
+
+	var b strings.Builder
+	b.WriteString(m.styles.AccentText.Render("Key Bindings"))
+	b.WriteString("\n\n")
+	b.WriteString(strings.Join(lines[m.offset:end], "\n"))
+	b.WriteString("\n\n")
+
+	footer := "Esc or ? to close"
+	if len(lines) > visible {
+		footer = "j/k to scroll  |  " + footer
+		switch {
+		case m.offset == 0:
+			footer += "  |  more below"
+		case end == len(lines):
+			footer += "  |  more above"
+		default:
+			footer += "  |  more above and below"
+		}
+	}
+	b.WriteString(m.styles.Muted.Render(util.Truncate(footer, m.boxWidth()-8)))
+
+	boxStyle := m.styles.Modal.Width(m.boxWidth() - 2)
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, boxStyle.Render(b.String()))
+}
@@ -0,0 +1,213 @@
+package modal
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ggfevans/endorse/internal/ui/styles"
+	"github.com/ggfevans/endorse/internal/util"
+)
+
+// PaletteItem is an entry in the command palette: an action or a
+// conversation.
+type PaletteItem struct {
+	ID     string
+	Title  string
+	Detail string // key for actions, participants for conversations
+	Kind   string
+	Recent bool
+}
+
+// PaletteSelectedMsg is sent when the user picks an entry.
+type PaletteSelectedMsg struct {
+	Item PaletteItem
+}
+
+// PaletteModel is the command palette: a query field over a list of
+// entries. The caller matches and ranks entries, handing them over with
+// SetItems whenever the query changes.
+type PaletteModel struct {
+	styles styles.Styles
+	width  int
+	height int
+	input  textinput.Model
+	items  []PaletteItem
+	cursor int
+	offset int
+	active bool
+}
+
+// NewPalette creates a new command palette.
+func NewPalette(s styles.Styles) PaletteModel {
+	input := textinput.New()
+	input.Placeholder = "Type an action or a name"
+	input.Prompt = "> "
+	input.CharLimit = 64
+	return PaletteModel{styles: s, input: input}
+}
+
+// Show opens the palette with an empty query and the entries for it.
+func (m *PaletteModel) Show(items []PaletteItem) tea.Cmd {
+	m.input.Reset()
+	m.active = true
+	m.SetItems(items)
+	return m.input.Focus()
+}
+
+// Query returns the text typed so far.
+func (m PaletteModel) Query() string {
+	return m.input.Value()
+}
+
+// SetItems replaces the entries, best first, and puts the cursor on the
+// first.
+func (m *PaletteModel) SetItems(items []PaletteItem) {
+	m.items = items
+	m.cursor = 0
+	m.offset = 0
+}
+
+// Hide dismisses the palette.
+func (m *PaletteModel) Hide() {
+	m.active = false
+	m.input.Blur()
+}
+
+// Active returns whether the palette is showing.
+func (m PaletteModel) Active() bool {
+	return m.active
+}
+
+// SetSize updates the modal dimensions.
+func (m *PaletteModel) SetSize(w, h int) {
+	m.width = w
+	m.height = h
+	m.input.Width = m.boxWidth() - 12
+	m.ensureVisible()
+}
+
+// SetStyles updates styles.
+func (m *PaletteModel) SetStyles(s styles.Styles) {
+	m.styles = s
+}
+
+// Update handles tea messages. Esc closes the palette; anything not used
+// for moving or picking edits the query.
+func (m PaletteModel) Update(msg tea.Msg) (PaletteModel, tea.Cmd) {
+	if !m.active {
+		return m, nil
+	}
+
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.Type {
+		case tea.KeyEsc:
+			m.Hide()
+			return m, nil
+		case tea.KeyEnter:
+			if len(m.items) == 0 {
+				return m, nil
+			}
+			item := m.items[m.cursor]
+			m.Hide()
+			return m, func() tea.Msg { return PaletteSelectedMsg{Item: item} }
+		case tea.KeyUp, tea.KeyCtrlP:
+			if m.cursor > 0 {
+				m.cursor--
+			}
+			m.ensureVisible()
+			return m, nil
+		case tea.KeyDown, tea.KeyCtrlN:
+			if m.cursor < len(m.items)-1 {
+				m.cursor++
+			}
+			m.ensureVisible()
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// boxWidth is the width of the box, borders included.
+func (m PaletteModel) boxWidth() int {
+	w := 72
+	if m.width > 0 && m.width < w+4 {
+		w = m.width - 4
+	}
+	return max(w, 30)
+}
+
+// visibleRows is how many entries fit on screen: the box's borders,
+// padding, query field and footer take 10 lines.
+func (m PaletteModel) visibleRows() int {
+	if m.height == 0 {
+		return 10
+	}
+	return max(min(m.height-10, 10), 1)
+}
+
+func (m *PaletteModel) ensureVisible() {
+	rows := m.visibleRows()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+rows {
+		m.offset = m.cursor - rows + 1
+	}
+}
+
+// View renders the palette centered on screen.
+func (m PaletteModel) View() string {
+	if !m.active {
+		return ""
+	}
+
+	inner := m.boxWidth() - 8
+	var b strings.Builder
+	b.WriteString(m.styles.AccentText.Render("Command Palette"))
+	b.WriteString("\n\n")
+	b.WriteString(m.input.View())
+	b.WriteString("\n\n")
+
+	rowStyle := m.styles.Text
+	end := min(m.offset+m.visibleRows(), len(m.items))
+	for i := m.offset; i < end; i++ {
+		item := m.items[i]
+		kind := item.Kind
+		if item.Recent {
+			kind = "recent"
+		}
+		// Two columns for the cursor, then the title and detail, with the
+		// kind right-aligned
+		room := inner - 2 - len(kind) - 1
+		title := util.Truncate(item.Title, room)
+		detail := ""
+		if item.Detail != "" && len([]rune(title))+3 < room {
+			detail = "  " + util.Truncate(item.Detail, room-len([]rune(title))-2)
+		}
+		gap := strings.Repeat(" ", max(room-len([]rune(title+detail)), 0)+1)
+
+		if i == m.cursor {
+			b.WriteString(m.styles.AccentText.Render("› ") + rowStyle.Bold(true).Render(title))
+		} else {
+			b.WriteString("  " + rowStyle.Render(title))
+		}
+		b.WriteString(m.styles.Muted.Render(detail + gap + kind))
+		b.WriteString("\n")
+	}
+	if len(m.items) == 0 {
+		b.WriteString(m.styles.Muted.Render("  No matches"))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.styles.Muted.Render(util.Truncate("Enter to run  |  ↑/↓ to choose  |  Esc to cancel", inner)))
+
+	boxStyle := m.styles.Modal.Width(m.boxWidth() - 2)
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, boxStyle.Render(b.String()))
+}
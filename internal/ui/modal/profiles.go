@@ -0,0 +1,217 @@
+package modal
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ggfevans/endorse/internal/ui/styles"
+)
+
+// ProfileSelectedMsg is sent when the user picks a profile to switch to.
+// New is set when the name was typed in rather than picked from the list.
+type ProfileSelectedMsg struct {
+	Name string
+	New  bool
+}
+
+// ProfilesModel is the account switcher: a list of profiles plus a field
+// for adding a new one.
+type ProfilesModel struct {
+	styles   styles.Styles
+	width    int
+	height   int
+	profiles []string
+	current  string
+	cursor   int
+	adding   bool
+	input    textinput.Model
+	validate func(string) error
+	err      string
+	active   bool
+}
+
+// NewProfiles creates a new account switcher. validate checks names typed
+// for new profiles.
+func NewProfiles(s styles.Styles, validate func(string) error) ProfilesModel {
+	input := textinput.New()
+	input.Placeholder = "work"
+	input.CharLimit = 32
+	input.Width = 32
+	return ProfilesModel{styles: s, input: input, validate: validate}
+}
+
+// Show opens the switcher with the cursor on the current profile.
+func (m *ProfilesModel) Show(profiles []string, current string) {
+	m.profiles = profiles
+	m.current = current
+	m.cursor = 0
+	for i, p := range profiles {
+		if p == current {
+			m.cursor = i
+		}
+	}
+	m.adding = false
+	m.err = ""
+	m.input.Reset()
+	m.input.Blur()
+	m.active = true
+}
+
+// Hide dismisses the switcher.
+func (m *ProfilesModel) Hide() {
+	m.active = false
+	m.adding = false
+	m.input.Blur()
+}
+
+// Active returns whether the switcher is showing.
+func (m ProfilesModel) Active() bool {
+	return m.active
+}
+
+// SetSize updates the modal dimensions.
+func (m *ProfilesModel) SetSize(w, h int) {
+	m.width = w
+	m.height = h
+}
+
+// SetStyles updates styles.
+func (m *ProfilesModel) SetStyles(s styles.Styles) {
+	m.styles = s
+}
+
+// Update handles tea messages. Esc closes the switcher, or leaves the new
+// profile field.
+func (m ProfilesModel) Update(msg tea.Msg) (ProfilesModel, tea.Cmd) {
+	if !m.active {
+		return m, nil
+	}
+
+	key, ok := msg.(tea.KeyMsg)
+	if m.adding {
+		if ok {
+			switch key.String() {
+			case "esc":
+				m.adding = false
+				m.err = ""
+				m.input.Blur()
+				return m, nil
+			case "enter":
+				return m.submitNew()
+			}
+		}
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+	}
+
+	if !ok {
+		return m, nil
+	}
+	switch key.String() {
+	case "esc":
+		m.Hide()
+	case "j", "down":
+		// The row after the last profile is "New profile…"
+		if m.cursor < len(m.profiles) {
+			m.cursor++
+		}
+	case "k", "up":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "n":
+		return m.startAdding()
+	case "enter":
+		if m.cursor == len(m.profiles) {
+			return m.startAdding()
+		}
+		name := m.profiles[m.cursor]
+		m.Hide()
+		return m, func() tea.Msg { return ProfileSelectedMsg{Name: name} }
+	}
+	return m, nil
+}
+
+func (m ProfilesModel) startAdding() (ProfilesModel, tea.Cmd) {
+	m.adding = true
+	m.cursor = len(m.profiles)
+	m.err = ""
+	m.input.Reset()
+	return m, m.input.Focus()
+}
+
+func (m ProfilesModel) submitNew() (ProfilesModel, tea.Cmd) {
+	name := strings.TrimSpace(m.input.Value())
+	if m.validate != nil {
+		if err := m.validate(name); err != nil {
+			m.err = err.Error()
+			return m, nil
+		}
+	}
+	for _, p := range m.profiles {
+		if p == name {
+			m.err = "profile " + name + " already exists"
+			return m, nil
+		}
+	}
+	m.Hide()
+	return m, func() tea.Msg { return ProfileSelectedMsg{Name: name, New: true} }
+}
+
+// View renders the switcher centered on screen.
+func (m ProfilesModel) View() string {
+	if !m.active {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(m.styles.AccentText.Render("Switch Account"))
+	b.WriteString("\n\n")
+
+	rowStyle := m.styles.Text
+	for i, p := range append(append([]string(nil), m.profiles...), "New profile…") {
+		label := p
+		if p == m.current {
+			label += m.styles.Muted.Render(" (current)")
+		}
+		if i == m.cursor && !m.adding {
+			b.WriteString(m.styles.AccentText.Render("› ") + rowStyle.Bold(true).Render(label))
+		} else {
+			b.WriteString("  " + rowStyle.Render(label))
+		}
+		b.WriteString("\n")
+	}
+
+	if m.adding {
+		b.WriteString("\n")
+		b.WriteString(m.input.View())
+		b.WriteString("\n")
+	}
+	if m.err != "" {
+		b.WriteString("\n")
+		b.WriteString(m.styles.ErrorText.Render("Error: " + m.err))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	if m.adding {
+		b.WriteString(m.styles.Muted.Render("Enter to create  |  Esc to go back"))
+	} else {
+		b.WriteString(m.styles.Muted.Render("Enter to switch  |  n new  |  Esc to cancel"))
+	}
+
+	boxWidth := 50
+	if m.width > 0 && m.width < boxWidth+10 {
+		boxWidth = m.width - 10
+	}
+	if boxWidth < 30 {
+		boxWidth = 30
+	}
+
+	boxStyle := m.styles.Modal.Width(boxWidth)
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, boxStyle.Render(b.String()))
+}
@@ -14,6 +14,15 @@ type Folder struct {
 	Count int
 }
 
+// Folder indices, in display order.
+const (
+	Inbox = iota
+	Unread
+	Pinned
+	Archived
+	Muted
+)
+
 // Model represents the sidebar panel.
 type Model struct {
 	styles   styles.Styles
@@ -31,6 +40,9 @@ func New(s styles.Styles) Model {
 		folders: []Folder{
 			{Name: "Inbox", Count: 0},
 			{Name: "Unread", Count: 0},
+			{Name: "Pinned", Count: 0},
+			{Name: "Archived", Count: 0},
+			{Name: "Muted", Count: 0},
 		},
 	}
 }
@@ -58,12 +70,25 @@ func (m *Model) SetStyles(s styles.Styles) {
 	m.styles = s
 }
 
-// SetCounts updates the folder counts.
-func (m *Model) SetCounts(inbox, unread int) {
-	m.folders[0].Count = inbox
-	m.folders[1].Count = unread
+// SetCounts updates the folder counts in display order.
+func (m *Model) SetCounts(counts ...int) {
+	for i, n := range counts {
+		if i < len(m.folders) {
+			m.folders[i].Count = n
+		}
+	}
+}
+
+// Select moves the selection to the given folder index.
+func (m *Model) Select(i int) {
+	if i >= 0 && i < len(m.folders) {
+		m.selected = i
+	}
 }
 
+// SelectedFolder returns the selected folder.
+func (m Model) SelectedFolder() Folder { return m.folders[m.selected] }
+
 // MoveDown moves selection down.
 func (m *Model) MoveDown() {
 	if m.selected < len(m.folders)-1 {
@@ -88,7 +113,7 @@ func (m Model) View() string {
 	title := m.styles.AccentText.Render("FOLDERS")
 	content := title + "\n\n"
 
-	accentBar := lipgloss.NewStyle().Foreground(m.styles.Theme.Secondary).Render("▎")
+	accentBar := m.styles.SelectionBar.Render("▎")
 
 	for i, f := range m.folders {
 		name := f.Name
@@ -101,6 +126,8 @@ func (m Model) View() string {
 		if w < 1 {
 			w = 1
 		}
+		// Keep each folder on one line, shortening the name before the count
+		name = util.Truncate(name, w-2-len(countStr))
 
 		prefix := "  "
 		if i == m.selected && m.focused {
@@ -111,12 +138,9 @@ func (m Model) View() string {
 		lineStyle := lipgloss.NewStyle().Width(w)
 
 		if i == m.selected && m.focused {
-			lineStyle = lineStyle.
-				Foreground(m.styles.Theme.Foreground)
+			lineStyle = m.styles.Text.Width(w)
 		} else if i == m.selected {
-			lineStyle = lineStyle.
-				Foreground(m.styles.Theme.Foreground).
-				Bold(true)
+			lineStyle = m.styles.Text.Width(w).Bold(true)
 		}
 
 		content += lineStyle.Render(line) + "\n"
@@ -50,13 +50,16 @@ func TestNavigation(t *testing.T) {
 		t.Errorf("expected Selected()=1 after MoveDown, got %d", m.Selected())
 	}
 
-	// MoveDown at max should not go above 1 (2 folders: index 0 and 1)
-	m.MoveDown()
-	if m.Selected() != 1 {
-		t.Errorf("expected Selected()=1 at upper bound, got %d", m.Selected())
+	// MoveDown at max should not go above 4 (5 folders: index 0 to 4)
+	for i := 0; i < 5; i++ {
+		m.MoveDown()
+	}
+	if m.Selected() != 4 {
+		t.Errorf("expected Selected()=4 at upper bound, got %d", m.Selected())
 	}
 
 	// MoveUp decreases Selected
+	m.Select(1)
 	m.MoveUp()
 	if m.Selected() != 0 {
 		t.Errorf("expected Selected()=0 after MoveUp, got %d", m.Selected())
@@ -133,10 +136,30 @@ func TestContent(t *testing.T) {
 
 	output := stripAnsi(m.View())
 
-	expected := []string{"FOLDERS", "Inbox", "Unread"}
+	expected := []string{"FOLDERS", "Inbox", "Unread", "Pinned", "Archived", "Muted"}
 	for _, s := range expected {
 		if !strings.Contains(output, s) {
 			t.Errorf("expected view to contain %q, got:\n%s", s, output)
 		}
 	}
 }
+
+func TestSelectFolder(t *testing.T) {
+	m := newTestSidebar()
+	m.SetSize(14, 20)
+	m.SetCounts(5, 3, 1, 0, 2)
+
+	m.Select(Archived)
+	if f := m.SelectedFolder(); f.Name != "Archived" {
+		t.Errorf("expected Archived selected, got %q", f.Name)
+	}
+	m.Select(99)
+	if m.Selected() != Archived {
+		t.Errorf("expected out-of-range Select to be ignored, got %d", m.Selected())
+	}
+
+	output := stripAnsi(m.View())
+	if !strings.Contains(output, "Pinned 1") || !strings.Contains(output, "Muted 2") {
+		t.Errorf("expected live counts for all folders, got:\n%s", output)
+	}
+}
@@ -21,23 +21,13 @@ type Model struct {
 	err       string
 	username  string
 	connected bool
+	notice    string
 }
 
-// New creates a new status bar model.
+// New creates a new status bar model. Hints are set by the caller, from
+// the keys in effect.
 func New(s styles.Styles) Model {
-	return Model{
-		styles: s,
-		hints: []Hint{
-			{Key: "↑↓/jk", Desc: "Navigate"},
-			{Key: "←→/Tab", Desc: "Focus"},
-			{Key: "Enter", Desc: "Select"},
-			{Key: "f", Desc: "Filter"},
-			{Key: "r", Desc: "Reply"},
-			{Key: "m", Desc: "Read/Unread"},
-			{Key: "d", Desc: "Delete"},
-			{Key: "q", Desc: "Quit"},
-		},
-	}
+	return Model{styles: s}
 }
 
 // SetWidth updates the status bar width.
@@ -55,6 +45,9 @@ func (m *Model) SetError(err string) {
 	m.err = err
 }
 
+// Error returns the current error message.
+func (m Model) Error() string { return m.err }
+
 // ClearError clears any error message.
 func (m *Model) ClearError() {
 	m.err = ""
@@ -70,6 +63,15 @@ func (m *Model) SetConnected(c bool) {
 	m.connected = c
 }
 
+// SetNotice sets a transient status shown ahead of the hints, such as the
+// realtime reconnect countdown. An empty string clears it.
+func (m *Model) SetNotice(n string) {
+	m.notice = n
+}
+
+// Notice returns the current notice.
+func (m Model) Notice() string { return m.notice }
+
 // SetStyles updates the styles.
 func (m *Model) SetStyles(s styles.Styles) {
 	m.styles = s
@@ -78,8 +80,7 @@ func (m *Model) SetStyles(s styles.Styles) {
 // View renders the status bar.
 func (m Model) View() string {
 	if m.err != "" {
-		errStyle := m.styles.StatusBar.Foreground(m.styles.Theme.Error)
-		return errStyle.Width(m.width).Render(fmt.Sprintf(" ERROR: %s", m.err))
+		return m.styles.StatusError.Width(m.width).Render(fmt.Sprintf(" ERROR: %s", m.err))
 	}
 
 	// Key hints (left-aligned)
@@ -89,6 +90,10 @@ func (m Model) View() string {
 		hintParts = append(hintParts, fmt.Sprintf("%s %s", key, h.Desc))
 	}
 	line := " " + strings.Join(hintParts, "  ")
+	if m.notice != "" {
+		notice := m.styles.StatusNotice.Render(m.notice)
+		line = " " + notice + "  " + strings.Join(hintParts, "  ")
+	}
 
 	return m.styles.StatusBar.Width(m.width).Render(line)
 }
@@ -1,7 +1,11 @@
 package styles
 
 import (
+	"io"
+
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
 	"github.com/ggfevans/endorse/internal/config"
 )
 
@@ -19,6 +23,9 @@ type Styles struct {
 	Muted      lipgloss.Style
 	Bold       lipgloss.Style
 	AccentText lipgloss.Style
+	Text       lipgloss.Style // plain text in the theme's foreground
+	ErrorText  lipgloss.Style
+	Spinner    lipgloss.Style
 
 	// Status indicators
 	Connected    lipgloss.Style
@@ -26,14 +33,18 @@ type Styles struct {
 	Unread       lipgloss.Style
 
 	// Header / status bar
-	Header    lipgloss.Style
-	StatusBar lipgloss.Style
-	StatusKey lipgloss.Style
+	Header       lipgloss.Style
+	StatusBar    lipgloss.Style
+	StatusKey    lipgloss.Style
+	StatusError  lipgloss.Style
+	StatusNotice lipgloss.Style
 
-	// Conversation list
+	// Conversation list and sidebar
 	ConvSelected lipgloss.Style
 	ConvNormal   lipgloss.Style
 	ConvUnread   lipgloss.Style
+	SelectionBar lipgloss.Style // bar beside the selected row
+	ActiveTab    lipgloss.Style
 
 	// Messages
 	OwnBubble     lipgloss.Style
@@ -41,100 +52,197 @@ type Styles struct {
 	Timestamp     lipgloss.Style
 	SenderName    lipgloss.Style
 	OwnSenderName lipgloss.Style
+	OwnBar        lipgloss.Style // bar beside the user's own messages
+	Match         lipgloss.Style // search term highlight
+	Retrying      lipgloss.Style // unsent message waiting to retry
+	Failed        lipgloss.Style // unsent message that gave up
 
 	// Compose
 	ComposeCursor lipgloss.Style
+
+	// Modal boxes, and the one for confirming something destructive
+	Modal       lipgloss.Style
+	ModalDanger lipgloss.Style
 }
 
-// New creates a Styles set from a theme.
+// New creates a Styles set from a theme for the terminal endorse is
+// running in.
 func New(theme config.Theme) Styles {
+	return NewFor(theme, DetectTerminal())
+}
+
+// NewFor creates a Styles set from a theme for a given terminal. On 256
+// colors the theme's hex values are rounded to the nearest the terminal
+// has; on 16 colors, which vary too much between terminals for that, the
+// theme's hues are mapped onto the basic colors and its greys onto ones
+// suited to the background. High contrast and terminals without color get
+// no colors at all.
+func NewFor(theme config.Theme, t Terminal) Styles {
+	r := lipgloss.NewRenderer(io.Discard)
+
+	mono := t.HighContrast || t.Profile == termenv.Ascii
+	switch {
+	case mono:
+		// Bold, underline and reverse video still need escape codes
+		r.SetColorProfile(termenv.ANSI)
+		theme = monoTheme(theme.Name)
+	case t.Profile == termenv.ANSI:
+		r.SetColorProfile(termenv.ANSI)
+		theme = ansiTheme(theme, t.DarkBackground)
+	default:
+		r.SetColorProfile(t.Profile)
+	}
+
+	s := build(r, theme)
+	if mono {
+		// Without color, focus, selection, matches and errors need other cues
+		s.BorderNormal = r.NewStyle().Border(lipgloss.NormalBorder())
+		s.BorderFocused = r.NewStyle().Border(lipgloss.ThickBorder())
+		s.ConvSelected = r.NewStyle().Reverse(true)
+		s.Match = r.NewStyle().Reverse(true)
+		s.ComposeCursor = r.NewStyle().Reverse(true)
+		s.ErrorText = r.NewStyle().Bold(true)
+		s.StatusError = s.StatusBar.Reverse(true).Bold(true)
+		s.StatusNotice = s.StatusBar.Bold(true)
+		s.Retrying = r.NewStyle().Bold(true)
+		s.Failed = r.NewStyle().Reverse(true).Bold(true)
+		s.ModalDanger = s.Modal.Border(lipgloss.ThickBorder())
+	}
+	return s
+}
+
+// build derives the styles from a theme's colors.
+func build(r *lipgloss.Renderer, theme config.Theme) Styles {
 	s := Styles{Theme: theme}
 
-	s.BorderNormal = lipgloss.NewStyle().
+	s.BorderNormal = r.NewStyle().
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(theme.Border)
 
-	s.BorderFocused = lipgloss.NewStyle().
+	s.BorderFocused = r.NewStyle().
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(theme.BorderFocused)
 
-	s.Title = lipgloss.NewStyle().
+	s.Title = r.NewStyle().
 		Foreground(theme.Foreground).
 		Bold(true)
 
-	s.Subtitle = lipgloss.NewStyle().
+	s.Subtitle = r.NewStyle().
 		Foreground(theme.Comment)
 
-	s.Muted = lipgloss.NewStyle().
+	s.Muted = r.NewStyle().
 		Foreground(theme.Subtle)
 
-	s.Bold = lipgloss.NewStyle().
+	s.Bold = r.NewStyle().
 		Foreground(theme.Foreground).
 		Bold(true)
 
-	s.AccentText = lipgloss.NewStyle().
+	s.AccentText = r.NewStyle().
 		Foreground(theme.Accent).
 		Bold(true)
 
-	s.Connected = lipgloss.NewStyle().
+	s.Text = r.NewStyle().
+		Foreground(theme.Foreground)
+
+	s.ErrorText = r.NewStyle().
+		Foreground(theme.Error)
+
+	s.Spinner = r.NewStyle().
+		Foreground(theme.Accent)
+
+	s.Connected = r.NewStyle().
 		Foreground(theme.Success).
 		Bold(true)
 
-	s.Disconnected = lipgloss.NewStyle().
+	s.Disconnected = r.NewStyle().
 		Foreground(theme.Error).
 		Bold(true)
 
-	s.Unread = lipgloss.NewStyle().
+	s.Unread = r.NewStyle().
 		Foreground(theme.Unread).
 		Bold(true)
 
-	s.Header = lipgloss.NewStyle().
+	s.Header = r.NewStyle().
 		Foreground(theme.Foreground).
 		Bold(true).
 		Padding(0, 1)
 
-	s.StatusBar = lipgloss.NewStyle().
+	s.StatusBar = r.NewStyle().
 		Foreground(theme.Comment).
 		Padding(0, 1)
 
-	s.StatusKey = lipgloss.NewStyle().
+	s.StatusKey = r.NewStyle().
 		Foreground(theme.Accent).
 		Bold(true)
 
-	s.ConvSelected = lipgloss.NewStyle().
+	s.StatusError = s.StatusBar.
+		Foreground(theme.Error)
+
+	s.StatusNotice = s.StatusBar.
+		Foreground(theme.Warning)
+
+	s.ConvSelected = r.NewStyle().
 		Background(theme.Selection).
 		Foreground(theme.Foreground)
 
-	s.ConvNormal = lipgloss.NewStyle().
+	s.ConvNormal = r.NewStyle().
 		Foreground(theme.Foreground)
 
-	s.ConvUnread = lipgloss.NewStyle().
+	s.ConvUnread = r.NewStyle().
 		Foreground(theme.Foreground).
 		Bold(true)
 
-	s.OwnBubble = lipgloss.NewStyle().
+	s.SelectionBar = r.NewStyle().
+		Foreground(theme.Secondary)
+
+	s.ActiveTab = r.NewStyle().
+		Foreground(theme.Secondary).
+		Bold(true)
+
+	s.OwnBubble = r.NewStyle().
 		Background(theme.OwnMessage).
 		Foreground(theme.Foreground).
 		Padding(0, 1)
 
-	s.OtherBubble = lipgloss.NewStyle().
+	s.OtherBubble = r.NewStyle().
 		Background(theme.OtherMessage).
 		Foreground(theme.Foreground).
 		Padding(0, 1)
 
-	s.Timestamp = lipgloss.NewStyle().
+	s.Timestamp = r.NewStyle().
 		Foreground(theme.Subtle)
 
-	s.SenderName = lipgloss.NewStyle().
+	s.SenderName = r.NewStyle().
 		Foreground(theme.Accent).
 		Bold(true)
 
-	s.OwnSenderName = lipgloss.NewStyle().
+	s.OwnSenderName = r.NewStyle().
 		Foreground(theme.OwnSender).
 		Bold(true)
 
-	s.ComposeCursor = lipgloss.NewStyle().
+	s.OwnBar = r.NewStyle().
+		Foreground(theme.OwnSender)
+
+	s.Match = r.NewStyle().
+		Foreground(theme.Background).
+		Background(theme.Warning)
+
+	s.Retrying = r.NewStyle().
+		Foreground(theme.Warning)
+
+	s.Failed = r.NewStyle().
+		Foreground(theme.Error)
+
+	s.ComposeCursor = r.NewStyle().
 		Foreground(theme.OwnSender)
 
+	s.Modal = r.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Accent).
+		Padding(1, 3)
+
+	s.ModalDanger = s.Modal.
+		BorderForeground(theme.Error)
+
 	return s
 }
@@ -0,0 +1,178 @@
+package styles
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"github.com/ggfevans/endorse/internal/config"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+var terminals = []struct {
+	name string
+	term Terminal
+}{
+	{"truecolor", Terminal{Profile: termenv.TrueColor, DarkBackground: true}},
+	{"ansi256", Terminal{Profile: termenv.ANSI256, DarkBackground: true}},
+	{"ansi-dark", Terminal{Profile: termenv.ANSI, DarkBackground: true}},
+	{"ansi-light", Terminal{Profile: termenv.ANSI}},
+	{"ascii", Terminal{Profile: termenv.Ascii, DarkBackground: true}},
+	{"high-contrast", Terminal{Profile: termenv.TrueColor, DarkBackground: true, HighContrast: true}},
+}
+
+// sample renders one of each kind of styled element.
+func sample(s Styles) string {
+	return strings.Join([]string{
+		s.BorderNormal.Render("panel"),
+		s.BorderFocused.Render("focused panel"),
+		s.Header.Render("Endorse") + s.Muted.Render("@me"),
+		s.Connected.Render("connected") + " " + s.Disconnected.Render("offline"),
+		s.ConvSelected.Render("Karl Havoc") + " " + s.ConvUnread.Render("Tammy") + s.Unread.Render(" 2"),
+		s.ConvNormal.Render("Read conversation") + " " + s.Timestamp.Render("5m ago"),
+		s.SenderName.Render("Karl") + " " + s.OtherBubble.Render("see you at the meetup"),
+		s.OwnSenderName.Render("You") + " " + s.OwnBubble.Render("on my way"),
+		"found a " + s.Match.Render("match") + " " + s.StatusKey.Render("q") + s.StatusBar.Render("Quit"),
+		s.ActiveTab.Render("Inbox 3") + " " + s.SelectionBar.Render("▎") + s.Text.Render("Alice") + " " + s.Spinner.Render("•••"),
+		s.OwnBar.Render("▎") + s.Retrying.Render("⟳ retrying") + " " + s.Failed.Render("✗ Not sent") + " " + s.ComposeCursor.Render(" "),
+		s.StatusError.Render(" ERROR: offline") + s.StatusNotice.Render("Reconnecting") + " " + s.ErrorText.Render("Error: bad cookie"),
+		s.Modal.Render("dialog") + s.ModalDanger.Render("delete?"),
+	}, "\n")
+}
+
+func TestSnapshots(t *testing.T) {
+	for _, tt := range terminals {
+		t.Run(tt.name, func(t *testing.T) {
+			got := strings.ReplaceAll(sample(NewFor(config.Dracula, tt.term)), "\x1b", `\e`) + "\n"
+			path := filepath.Join("testdata", tt.name+".golden")
+			if *update {
+				if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("%v (run go test -update to create it)", err)
+			}
+			if got != string(want) {
+				t.Errorf("rendering changed for %s; run go test -update if intended\ngot:\n%s\nwant:\n%s", tt.name, got, want)
+			}
+		})
+	}
+}
+
+var sgr = regexp.MustCompile(`\x1b\[([0-9;]*)m`)
+
+// colorCodes returns the color parameters of every SGR sequence in out.
+func colorCodes(out string) []string {
+	var codes []string
+	for _, m := range sgr.FindAllStringSubmatch(out, -1) {
+		params := strings.Split(m[1], ";")
+		for i := 0; i < len(params); i++ {
+			switch p := params[i]; {
+			case p == "38" || p == "48":
+				codes = append(codes, strings.Join(params[i:], ";"))
+				i = len(params)
+			case len(p) == 2 && (p[0] == '3' || p[0] == '4' || p[0] == '9') && p != "39" && p != "49",
+				len(p) == 3 && strings.HasPrefix(p, "10"):
+				codes = append(codes, p)
+			}
+		}
+	}
+	return codes
+}
+
+func TestColorDepth(t *testing.T) {
+	for _, tt := range terminals {
+		codes := colorCodes(sample(NewFor(config.Dracula, tt.term)))
+		joined := strings.Join(codes, " ")
+		switch tt.term.Profile {
+		case termenv.TrueColor:
+			if tt.term.HighContrast {
+				if len(codes) > 0 {
+					t.Errorf("%s: expected no colors, got %v", tt.name, codes)
+				}
+			} else if !strings.Contains(joined, "38;2;") {
+				t.Errorf("%s: expected 24-bit colors, got %v", tt.name, codes)
+			}
+		case termenv.ANSI256:
+			if !strings.Contains(joined, "38;5;") || strings.Contains(joined, "38;2;") {
+				t.Errorf("%s: expected only 256-color codes, got %v", tt.name, codes)
+			}
+		case termenv.ANSI:
+			if len(codes) == 0 || strings.Contains(joined, "38;") || strings.Contains(joined, "48;") {
+				t.Errorf("%s: expected only the 16 basic colors, got %v", tt.name, codes)
+			}
+		case termenv.Ascii:
+			if len(codes) > 0 {
+				t.Errorf("%s: expected no colors, got %v", tt.name, codes)
+			}
+		}
+	}
+}
+
+func TestHighContrastMarksFocusWithoutColor(t *testing.T) {
+	s := NewFor(config.Dracula, Terminal{Profile: termenv.TrueColor, HighContrast: true})
+	if s.BorderNormal.Render("x") == s.BorderFocused.Render("x") {
+		t.Error("expected focused panels to look different without color")
+	}
+	if s.ConvSelected.Render("x") == s.ConvNormal.Render("x") {
+		t.Error("expected the selection to look different without color")
+	}
+	for name, style := range map[string]lipgloss.Style{
+		"failed":       s.Failed,
+		"retrying":     s.Retrying,
+		"error":        s.ErrorText,
+		"status error": s.StatusError,
+	} {
+		if style.Render("x") == s.Text.Render("x") {
+			t.Errorf("expected %s text to stand out without color", name)
+		}
+	}
+	if s.Failed.Render("x") == s.Retrying.Render("x") {
+		t.Error("expected failed and retrying messages to look different without color")
+	}
+	if s.ModalDanger.Render("x") == s.Modal.Render("x") {
+		t.Error("expected the delete confirmation to look different without color")
+	}
+}
+
+func TestANSIPaletteFollowsTheme(t *testing.T) {
+	dark := Terminal{Profile: termenv.ANSI, DarkBackground: true}
+	dracula := NewFor(config.Dracula, dark).Theme
+	if dracula.Secondary != "13" || dracula.Success != "10" || dracula.Error != "9" {
+		t.Errorf("expected dracula's pink, green and red as bright basic colors, got %q %q %q", dracula.Secondary, dracula.Success, dracula.Error)
+	}
+
+	light := NewFor(config.GitHubLight, Terminal{Profile: termenv.ANSI}).Theme
+	if light.Accent != "4" || light.Secondary != "5" {
+		t.Errorf("expected github-light's blue and purple as normal basic colors, got %q %q", light.Accent, light.Secondary)
+	}
+	if light.Border != "7" || light.Foreground != "" {
+		t.Errorf("expected greys to follow the background, got border %q foreground %q", light.Border, light.Foreground)
+	}
+
+	// Themes with different hues no longer look the same
+	custom := config.Dracula
+	custom.Accent = "#50c0c0"
+	if got := NewFor(custom, dark).Theme.Accent; got == dracula.Accent || got != "14" {
+		t.Errorf("expected a teal accent to become bright cyan, got %q", got)
+	}
+}
+
+func TestDefaultThemeFollowsBackground(t *testing.T) {
+	if got := DefaultTheme(Terminal{DarkBackground: true}); got != config.Dracula {
+		t.Errorf("expected dracula on a dark background, got %q", got.Name)
+	}
+	if got := DefaultTheme(Terminal{}); got.Name != "github-light" {
+		t.Errorf("expected a light theme on a light background, got %q", got.Name)
+	}
+}
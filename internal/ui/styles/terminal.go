@@ -0,0 +1,151 @@
+package styles
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"github.com/ggfevans/endorse/internal/config"
+)
+
+// Terminal describes what the terminal can display.
+type Terminal struct {
+	Profile        termenv.Profile // color support: TrueColor, ANSI256, ANSI or Ascii
+	DarkBackground bool
+
+	// HighContrast drops color entirely, marking focus, selection and
+	// emphasis with borders, bold and reverse video instead
+	HighContrast bool
+}
+
+// DetectTerminal reads the terminal's color support and background. NO_COLOR
+// turns on high contrast rather than removing all styling, since bold and
+// reverse video carry meaning too.
+func DetectTerminal() Terminal {
+	out := lipgloss.DefaultRenderer().Output()
+	t := Terminal{
+		Profile:        out.EnvColorProfile(),
+		DarkBackground: lipgloss.HasDarkBackground(),
+	}
+	if out.EnvNoColor() {
+		t.Profile = out.ColorProfile()
+		t.HighContrast = true
+	}
+	return t
+}
+
+// DefaultTheme returns the theme used when none is configured: Dracula on a
+// dark background, GitHub Light on a light one.
+func DefaultTheme(t Terminal) config.Theme {
+	if t.DarkBackground {
+		return config.Dracula
+	}
+	return config.GitHubLight
+}
+
+// ansiTheme maps a theme onto the 16 basic colors, which terminals define
+// for themselves. Each of the theme's colors takes the basic color nearest
+// its hue; greys, and the backgrounds, take ones suited to the terminal's
+// background instead. Text keeps the terminal's own foreground, and the
+// bright variants are used only where they read well on the background.
+func ansiTheme(theme config.Theme, dark bool) config.Theme {
+	pick := func(onDark, onLight string) lipgloss.Color {
+		if dark {
+			return lipgloss.Color(onDark)
+		}
+		return lipgloss.Color(onLight)
+	}
+	hue := func(c lipgloss.Color, onDark, onLight string) lipgloss.Color {
+		if code, ok := ansiHue(c); ok {
+			if dark {
+				code += 8
+			}
+			return lipgloss.Color(strconv.Itoa(code))
+		}
+		return pick(onDark, onLight)
+	}
+	accentDim := lipgloss.Color("4")
+	if code, ok := ansiHue(theme.AccentDim); ok {
+		accentDim = lipgloss.Color(strconv.Itoa(code))
+	}
+	return config.Theme{
+		Name:          theme.Name,
+		Background:    lipgloss.Color("0"),
+		Foreground:    lipgloss.Color(""),
+		CurrentLine:   pick("8", "7"),
+		Selection:     pick("8", "7"),
+		Comment:       pick("7", "8"),
+		Subtle:        pick("7", "8"),
+		Primary:       hue(theme.Primary, "13", "5"),
+		Secondary:     hue(theme.Secondary, "13", "5"),
+		Success:       hue(theme.Success, "10", "2"),
+		Warning:       hue(theme.Warning, "11", "3"),
+		Error:         hue(theme.Error, "9", "1"),
+		Info:          hue(theme.Info, "14", "6"),
+		Accent:        hue(theme.Accent, "13", "5"),
+		AccentDim:     accentDim,
+		Border:        pick("8", "7"),
+		BorderFocused: hue(theme.BorderFocused, "13", "5"),
+		Unread:        hue(theme.Unread, "10", "2"),
+		OwnMessage:    pick("8", "7"),
+		OtherMessage:  lipgloss.Color(""),
+		OwnSender:     hue(theme.OwnSender, "14", "6"),
+	}
+}
+
+// ansiHues are the basic colors by the hue, in degrees, where each ends.
+// Terminals draw magenta anywhere from violet to pink, so it takes purples
+// that an even split would give to blue.
+var ansiHues = []struct {
+	end  float64
+	code int
+}{
+	{20, 1},  // red
+	{75, 3},  // yellow, with orange
+	{165, 2}, // green
+	{200, 6}, // cyan
+	{255, 4}, // blue
+	{330, 5}, // magenta, with purple and pink
+	{360, 1}, // red again
+}
+
+// ansiHue returns the basic color (1-6) nearest a hex color's hue, or false
+// for a grey or a color that isn't hex.
+func ansiHue(c lipgloss.Color) (int, bool) {
+	s := strings.TrimPrefix(string(c), "#")
+	rgb, err := strconv.ParseUint(s, 16, 32)
+	if len(s) != 6 || err != nil {
+		return 0, false
+	}
+	r, g, b := float64(rgb>>16&0xff), float64(rgb>>8&0xff), float64(rgb&0xff)
+	hi, lo := max(r, g, b), min(r, g, b)
+	if hi-lo < 48 {
+		return 0, false // too little color to tell a hue
+	}
+
+	var h float64
+	switch hi {
+	case r:
+		h = math.Mod((g-b)/(hi-lo)+6, 6)
+	case g:
+		h = 2 + (b-r)/(hi-lo)
+	default:
+		h = 4 + (r-g)/(hi-lo)
+	}
+	h *= 60
+	for _, a := range ansiHues {
+		if h < a.end {
+			return a.code, true
+		}
+	}
+	return 1, true
+}
+
+// monoTheme is a theme with every color unset, so text is drawn in the
+// terminal's own colors.
+func monoTheme(name string) config.Theme {
+	return config.Theme{Name: name}
+}
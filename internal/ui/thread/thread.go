@@ -2,6 +2,7 @@ package thread
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/ggfevans/endorse/internal/search"
 	"github.com/ggfevans/endorse/internal/ui/styles"
 	"github.com/ggfevans/endorse/internal/util"
 )
@@ -19,15 +21,47 @@ var typingDots = spinner.Spinner{
 	FPS:    300 * time.Millisecond,
 }
 
+// Delivery is the delivery state of one of the user's own messages.
+type Delivery int
+
+const (
+	// Delivered messages are confirmed by the server and render normally.
+	Delivered Delivery = iota
+	// Retrying messages failed to send and will be retried automatically.
+	Retrying
+	// Failed messages ran out of automatic retries.
+	Failed
+	// Sending messages are local placeholders awaiting the server.
+	Sending
+	// Sent messages were confirmed by the server during this session.
+	Sent
+	// Queued messages haven't been sent yet, e.g. while signing in.
+	Queued
+)
+
 // Message represents a single message in a thread.
 type Message struct {
 	ID        string
 	Sender    string
 	Body      string
-	Timestamp string
+	Timestamp time.Time
 	IsOwn     bool
+	Delivery  Delivery
+	Attempts  int // failed send attempts, for unsent messages
 }
 
+// Receipt records that a participant has seen the conversation up to and
+// including a message.
+type Receipt struct {
+	Reader    string
+	MessageID string // message the receipt refers to, which may not be loaded
+	SeenAt    time.Time
+}
+
+// maxReaderNames is how many readers are named in a seen marker before the
+// rest are summarised as a count.
+const maxReaderNames = 3
+
 // Model represents the message thread panel.
 type Model struct {
 	styles         styles.Styles
@@ -38,10 +72,22 @@ type Model struct {
 	messages       []Message
 	conversationID string
 	viewport       viewport.Model
-	typingName     string        // who is typing ("" = nobody)
-	typingSpinner  spinner.Model // animation driver
-	composeView    string        // pre-rendered compose view
-	hasCompose     bool          // whether compose is embedded
+	typingName     string         // who is typing ("" = nobody)
+	typingSpinner  spinner.Model  // animation driver
+	composeView    string         // pre-rendered compose view
+	hasCompose     bool           // whether compose is embedded
+	loadingOlder   bool           // older page request in flight
+	reachedStart   bool           // no older messages left to load
+	unsent         []Message      // outbox entries rendered after messages
+	highlight      []string       // search terms to highlight in bodies
+	jumpTarget     string         // message ID to scroll to once loaded
+	messageLines   map[string]int // first content line of each message
+	receipts       []Receipt      // latest seen receipt per participant
+	absolute       bool           // show clock times instead of relative ones
+	selected       string         // message whose exact time is shown ("" = none)
+	groupGap       time.Duration  // silence that splits a sender's run (0 = never)
+	retryKey       string         // key shown for retrying unsent messages ("" = unbound)
+	discardKey     string         // key shown for discarding them ("" = unbound)
 }
 
 // New creates a new thread model.
@@ -49,8 +95,16 @@ func New(s styles.Styles) Model {
 	vp := viewport.New(0, 0)
 	sp := spinner.New()
 	sp.Spinner = typingDots
-	sp.Style = lipgloss.NewStyle().Foreground(s.Theme.Accent)
-	return Model{styles: s, viewport: vp, typingSpinner: sp}
+	sp.Style = s.Spinner
+	return Model{styles: s, viewport: vp, typingSpinner: sp, retryKey: "R", discardKey: "X"}
+}
+
+// SetUnsentKeys sets the keys named under unsent messages for retrying and
+// discarding them. An empty key leaves that action out.
+func (m *Model) SetUnsentKeys(retry, discard string) {
+	m.retryKey = retry
+	m.discardKey = discard
+	m.refreshContent()
 }
 
 // SetComposeView sets the pre-rendered compose view for embedded rendering.
@@ -80,11 +134,17 @@ func (m *Model) SetSize(w, h int) {
 	m.refreshContent()
 }
 
-// Focus gives focus.
-func (m *Model) Focus() { m.focused = true }
+// Focus gives focus and selects the message at the bottom of the view.
+func (m *Model) Focus() {
+	m.focused = true
+	m.selectVisible()
+}
 
-// Blur removes focus.
-func (m *Model) Blur() { m.focused = false }
+// Blur removes focus and clears the selected message.
+func (m *Model) Blur() {
+	m.focused = false
+	m.selectMessage("")
+}
 
 // Focused returns focus state.
 func (m Model) Focused() bool { return m.focused }
@@ -92,6 +152,8 @@ func (m Model) Focused() bool { return m.focused }
 // SetStyles updates the styles.
 func (m *Model) SetStyles(s styles.Styles) {
 	m.styles = s
+	m.typingSpinner.Style = s.Spinner
+	m.refreshContent()
 }
 
 // SetConversation sets the current conversation.
@@ -100,10 +162,70 @@ func (m *Model) SetConversation(id, subject string) {
 	m.subject = subject
 	m.messages = nil
 	m.typingName = ""
+	m.loadingOlder = false
+	m.reachedStart = false
+	m.unsent = nil
+	m.highlight = nil
+	m.jumpTarget = ""
+	m.receipts = nil
+	m.selected = ""
 	m.refreshContent()
 	m.viewport.GotoTop()
 }
 
+// SetUnsent replaces the outbox entries shown below the messages.
+func (m *Model) SetUnsent(msgs []Message) {
+	grew := len(msgs) > len(m.unsent)
+	m.unsent = msgs
+	m.refreshContent()
+	if grew {
+		m.viewport.GotoBottom()
+	}
+}
+
+// UnsentCount returns the number of outbox entries shown.
+func (m Model) UnsentCount() int {
+	return len(m.unsent)
+}
+
+// SetHighlight sets search terms to highlight in message bodies.
+func (m *Model) SetHighlight(terms []string) {
+	m.highlight = terms
+	m.refreshContent()
+}
+
+// JumpTo scrolls to the given message, or remembers it and scrolls there
+// once it arrives. The target holds until the user scrolls, so pages that
+// arrive later don't move the view away from it.
+func (m *Model) JumpTo(messageID string) {
+	m.jumpTarget = messageID
+	m.scrollToTarget()
+}
+
+// JumpPending reports whether there is a jump target that hasn't been
+// loaded yet.
+func (m Model) JumpPending() bool {
+	if m.jumpTarget == "" {
+		return false
+	}
+	_, ok := m.messageLines[m.jumpTarget]
+	return !ok
+}
+
+// scrollToTarget centres the jump target in the viewport if it is loaded.
+// It returns false if there is no target or it hasn't been loaded yet.
+func (m *Model) scrollToTarget() bool {
+	if m.jumpTarget == "" {
+		return false
+	}
+	line, ok := m.messageLines[m.jumpTarget]
+	if !ok {
+		return false
+	}
+	m.viewport.SetYOffset(max(line-m.viewport.Height/2, 0))
+	return true
+}
+
 // SetTyping shows the typing indicator and starts animation.
 // Returns a Cmd to start the spinner ticker.
 func (m *Model) SetTyping(name string) tea.Cmd {
@@ -146,28 +268,273 @@ func (m Model) ConversationID() string {
 	return m.conversationID
 }
 
-// SetMessages replaces the message list.
+// SetMessages replaces the message list and scrolls to the bottom, or to
+// the pending jump target if it is among the messages.
 func (m *Model) SetMessages(msgs []Message) {
 	m.messages = msgs
 	m.refreshContent()
-	m.viewport.GotoBottom()
+	if !m.scrollToTarget() {
+		m.viewport.GotoBottom()
+	}
+	m.selectVisible()
 }
 
-// AppendMessage adds a message at the end.
+// PrependMessages inserts an older page of messages before the current ones.
+// Messages already in the thread are skipped, and the rest are kept in time
+// order, so a page that fills a gap below older cached messages lands in
+// place. The scroll position is preserved so the message the user was
+// reading stays in place, unless the page brings in the jump target.
+func (m *Model) PrependMessages(msgs []Message) {
+	seen := make(map[string]bool, len(m.messages))
+	for _, msg := range m.messages {
+		seen[msg.ID] = true
+	}
+	var older []Message
+	for _, msg := range msgs {
+		if !seen[msg.ID] {
+			older = append(older, msg)
+		}
+	}
+
+	before := m.viewport.TotalLineCount()
+	offset := m.viewport.YOffset
+	m.messages = append(older, m.messages...)
+	sort.SliceStable(m.messages, func(i, j int) bool {
+		return m.messages[i].Timestamp.Before(m.messages[j].Timestamp)
+	})
+	m.loadingOlder = false
+	m.refreshContent()
+	if !m.scrollToTarget() {
+		m.viewport.SetYOffset(offset + m.viewport.TotalLineCount() - before)
+	}
+}
+
+// SetLoadingOlder shows or hides the "loading older" row at the top.
+func (m *Model) SetLoadingOlder(loading bool) {
+	if m.loadingOlder == loading {
+		return
+	}
+	m.loadingOlder = loading
+	m.refreshContent()
+}
+
+// LoadingOlder returns whether an older page is being fetched.
+func (m Model) LoadingOlder() bool {
+	return m.loadingOlder
+}
+
+// SetReachedStart marks whether the beginning of the conversation is loaded.
+func (m *Model) SetReachedStart(reached bool) {
+	if m.reachedStart == reached {
+		return
+	}
+	m.reachedStart = reached
+	m.refreshContent()
+}
+
+// ReachedStart returns whether the beginning of the conversation is loaded.
+func (m Model) ReachedStart() bool {
+	return m.reachedStart
+}
+
+// AppendMessage adds a message at the end. A message whose ID is already
+// in the thread updates it in place instead, keeping any Sent marker.
 func (m *Model) AppendMessage(msg Message) {
+	if i := m.indexOf(msg.ID); i >= 0 {
+		if m.messages[i].Delivery == Sent {
+			msg.Delivery = Sent
+		}
+		m.messages[i] = msg
+		m.refreshContent()
+		return
+	}
 	m.messages = append(m.messages, msg)
 	m.refreshContent()
 	m.viewport.GotoBottom()
+	m.selectVisible()
+}
+
+// MergeMessages replaces the message list with a refreshed one that covers
+// the current messages, e.g. after a reconnect backfill. Sent markers are
+// kept. The view goes to the jump target if it's loaded, and otherwise
+// stays at the bottom only if it was already there.
+func (m *Model) MergeMessages(msgs []Message) {
+	sent := make(map[string]bool)
+	for _, msg := range m.messages {
+		if msg.Delivery == Sent {
+			sent[msg.ID] = true
+		}
+	}
+	for i := range msgs {
+		if sent[msgs[i].ID] {
+			msgs[i].Delivery = Sent
+		}
+	}
+
+	atBottom := m.viewport.AtBottom()
+	offset := m.viewport.YOffset
+	m.messages = msgs
+	m.refreshContent()
+	switch {
+	case m.scrollToTarget():
+	case atBottom:
+		m.viewport.GotoBottom()
+	default:
+		m.viewport.SetYOffset(offset)
+	}
+}
+
+// SetReceipts replaces the seen receipts, one per participant.
+func (m *Model) SetReceipts(receipts []Receipt) {
+	m.receipts = receipts
+	m.refreshContent()
+}
+
+// seenMarkers groups receipts under the last of the user's own messages
+// each reader has seen, keyed by message ID. A receipt for a message that
+// isn't loaded only counts for own messages sent before it was seen, so it
+// never marks a message the reader can't have read yet.
+func (m Model) seenMarkers() map[string][]Receipt {
+	markers := make(map[string][]Receipt)
+	for _, r := range m.receipts {
+		i := m.indexOf(r.MessageID)
+		known := i >= 0
+		if !known {
+			i = len(m.messages) - 1
+		}
+		for ; i >= 0; i-- {
+			if !known && m.messages[i].Timestamp.After(r.SeenAt) {
+				continue
+			}
+			if m.messages[i].IsOwn {
+				id := m.messages[i].ID
+				markers[id] = append(markers[id], r)
+				break
+			}
+		}
+	}
+	return markers
+}
+
+// seenLine renders "Seen by X · 2m ago" for a group of readers, using the
+// most recent time among them.
+func (m Model) seenLine(receipts []Receipt) string {
+	sort.SliceStable(receipts, func(i, j int) bool {
+		return receipts[i].SeenAt.Before(receipts[j].SeenAt)
+	})
+	var names []string
+	for i, r := range receipts {
+		if i == maxReaderNames {
+			names = append(names, fmt.Sprintf("+%d", len(receipts)-maxReaderNames))
+			break
+		}
+		names = append(names, r.Reader)
+	}
+	latest := receipts[len(receipts)-1].SeenAt
+	return m.styles.Muted.Render("Seen by " + strings.Join(names, ", ") + " · " + util.FormatTime(latest, m.absolute))
+}
+
+// ConfirmSent replaces the unsent placeholder localID with the message the
+// server confirmed, taking on its ID and timestamp.
+func (m *Model) ConfirmSent(localID string, msg Message) {
+	for i, u := range m.unsent {
+		if u.ID == localID {
+			m.unsent = append(m.unsent[:i:i], m.unsent[i+1:]...)
+			break
+		}
+	}
+	msg.Delivery = Sent
+	if i := m.indexOf(msg.ID); i >= 0 {
+		// Already arrived via realtime; just mark it sent
+		m.messages[i] = msg
+	} else {
+		m.messages = append(m.messages, msg)
+	}
+	m.refreshContent()
+	m.viewport.GotoBottom()
+}
+
+func (m Model) indexOf(id string) int {
+	for i, msg := range m.messages {
+		if msg.ID == id {
+			return i
+		}
+	}
+	return -1
 }
 
 // ScrollUp scrolls the view up.
 func (m *Model) ScrollUp(lines int) {
+	m.jumpTarget = ""
 	m.viewport.LineUp(lines)
+	m.selectVisible()
 }
 
 // ScrollDown scrolls the view down.
 func (m *Model) ScrollDown(lines int) {
+	m.jumpTarget = ""
 	m.viewport.LineDown(lines)
+	m.selectVisible()
+}
+
+// SetAbsoluteTimes switches between relative ("5m ago") and clock timestamps.
+func (m *Model) SetAbsoluteTimes(absolute bool) {
+	m.absolute = absolute
+	m.refreshContent()
+}
+
+// SetGroupGap sets how long a sender must be quiet before their next message
+// starts a new group. Zero disables gap splitting.
+func (m *Model) SetGroupGap(gap time.Duration) {
+	m.groupGap = gap
+	m.refreshContent()
+}
+
+// Refresh re-renders the messages in place so relative times stay current.
+func (m *Model) Refresh() {
+	m.refreshContent()
+}
+
+// HoverAt selects the message under a row of the viewport, so its exact
+// time is shown. Rows outside the viewport are ignored.
+func (m *Model) HoverAt(row int) {
+	if row < 0 || row >= m.viewport.Height {
+		return
+	}
+	m.selectMessage(m.messageAt(m.viewport.YOffset + row))
+}
+
+// SelectedMessage returns the ID of the message whose exact time is shown.
+func (m Model) SelectedMessage() string {
+	return m.selected
+}
+
+// selectVisible selects the message at the bottom of the view while focused.
+func (m *Model) selectVisible() {
+	if !m.focused {
+		return
+	}
+	m.selectMessage(m.messageAt(m.viewport.YOffset + m.viewport.Height - 1))
+}
+
+func (m *Model) selectMessage(id string) {
+	if m.selected == id {
+		return
+	}
+	m.selected = id
+	m.refreshContent()
+}
+
+// messageAt returns the message that content line falls within.
+func (m Model) messageAt(line int) string {
+	var id string
+	start := -1
+	for msgID, l := range m.messageLines {
+		if l <= line && l > start {
+			id, start = msgID, l
+		}
+	}
+	return id
 }
 
 // View renders the thread panel.
@@ -221,7 +588,8 @@ func (m Model) View() string {
 
 // refreshContent rebuilds the viewport content string from messages.
 func (m *Model) refreshContent() {
-	if len(m.messages) == 0 {
+	m.messageLines = make(map[string]int, len(m.messages))
+	if len(m.messages) == 0 && len(m.unsent) == 0 {
 		if m.conversationID != "" {
 			m.viewport.SetContent(m.styles.Muted.Render("  No messages"))
 		} else {
@@ -235,8 +603,8 @@ func (m *Model) refreshContent() {
 		contentWidth = 1
 	}
 
-	accentBar := lipgloss.NewStyle().Foreground(m.styles.Theme.OwnSender).Render("▎")
-	divider := m.styles.Muted.Render(strings.Repeat("─", contentWidth-2))
+	accentBar := m.styles.OwnBar.Render("▎")
+	divider := m.styles.Muted.Render(strings.Repeat("─", max(contentWidth-2, 0)))
 
 	// Word-wrap style for body text (account for prefix character)
 	bodyWidth := contentWidth - 2
@@ -249,14 +617,29 @@ func (m *Model) refreshContent() {
 	skipFirstSender := len(m.messages) > 0 && m.messages[0].Sender == m.subject
 
 	var lines []string
+	if m.loadingOlder {
+		lines = append(lines, m.styles.Muted.Render("  Loading older…"))
+	} else if m.reachedStart {
+		lines = append(lines, m.styles.Muted.Render("  Beginning of conversation"), "")
+	}
+
+	markers := m.seenMarkers()
+
 	var prevSender string
-	for _, msg := range m.messages {
+	var prevTime time.Time
+	for _, msg := range append(m.messages[:len(m.messages):len(m.messages)], m.unsent...) {
+		// Unsent entries have no timestamp and stay in the last group
+		newDay := !msg.Timestamp.IsZero() && (prevTime.IsZero() || !util.SameDay(msg.Timestamp, prevTime))
+		if newDay {
+			lines = append(lines, m.daySeparator(msg.Timestamp, contentWidth))
+		}
+
 		if msg.Sender != prevSender {
 			if prevSender == "" && skipFirstSender {
 				// First sender matches title — skip redundant header
 				prevSender = msg.Sender
 			} else {
-				if prevSender != "" {
+				if prevSender != "" && !newDay {
 					lines = append(lines, divider)
 				}
 
@@ -269,21 +652,32 @@ func (m *Model) refreshContent() {
 				lines = append(lines, header)
 				prevSender = msg.Sender
 			}
+		} else if !newDay && m.groupGap > 0 && !prevTime.IsZero() && msg.Timestamp.Sub(prevTime) > m.groupGap {
+			// Same sender after a long silence — split the run
+			lines = append(lines, divider)
+		}
+		if !msg.Timestamp.IsZero() {
+			prevTime = msg.Timestamp
 		}
 
 		prefix := " "
 		if msg.IsOwn {
 			prefix = accentBar
 		}
+		m.messageLines[msg.ID] = len(lines)
 		wrapped := wrapStyle.Render(msg.Body)
 		for i, line := range strings.Split(wrapped, "\n") {
+			line = m.highlightLine(line)
 			if i == 0 {
 				lines = append(lines, prefix+line)
 			} else {
 				lines = append(lines, " "+line)
 			}
 		}
-		lines = append(lines, " "+m.styles.Timestamp.Render(msg.Timestamp))
+		lines = append(lines, " "+m.deliveryLine(msg))
+		if readers := markers[msg.ID]; len(readers) > 0 && msg.Delivery != Sending && msg.Delivery != Queued && msg.Delivery != Retrying && msg.Delivery != Failed {
+			lines = append(lines, " "+m.seenLine(readers))
+		}
 	}
 
 	if m.typingName != "" {
@@ -299,13 +693,78 @@ func (m *Model) refreshContent() {
 	m.viewport.SetContent(strings.Join(lines, "\n"))
 }
 
-// Clear resets the thread.
+// daySeparator renders a centred "── Today ──" row for a calendar day.
+func (m Model) daySeparator(t time.Time, width int) string {
+	label := " " + util.DayLabel(t) + " "
+	side := max((width-lipgloss.Width(label))/2, 1)
+	return m.styles.Muted.Render(strings.Repeat("─", side) + label + strings.Repeat("─", side))
+}
+
+// deliveryLine renders the timestamp, or the delivery status of an unsent message.
+func (m Model) deliveryLine(msg Message) string {
+	switch msg.Delivery {
+	case Sending:
+		return m.styles.Muted.Render("… Sending")
+	case Queued:
+		return m.styles.Muted.Render("◷ Waiting to send" + m.unsentActions("now"))
+	case Sent:
+		return m.styles.Timestamp.Render(m.timeText(msg)) + m.styles.Muted.Render(" · ✓ Sent")
+	case Retrying:
+		return m.styles.Retrying.
+			Render(fmt.Sprintf("⟳ Not sent · retrying (attempt %d)", msg.Attempts+1) + m.unsentActions("now"))
+	case Failed:
+		return m.styles.Failed.
+			Render("✗ Not sent" + m.unsentActions("retry"))
+	}
+	return m.styles.Timestamp.Render(m.timeText(msg))
+}
+
+// unsentActions lists the keys for an unsent message, calling the retry
+// key's action retry.
+func (m Model) unsentActions(retry string) string {
+	var s string
+	if m.retryKey != "" {
+		s += " · " + m.retryKey + " " + retry
+	}
+	if m.discardKey != "" {
+		s += " · " + m.discardKey + " discard"
+	}
+	return s
+}
+
+// timeText formats a message's timestamp, in full for the selected message.
+func (m Model) timeText(msg Message) string {
+	if msg.ID == m.selected && !msg.Timestamp.IsZero() {
+		return util.ExactTime(msg.Timestamp)
+	}
+	return util.FormatTime(msg.Timestamp, m.absolute)
+}
+
+// highlightLine styles every occurrence of the highlight terms in a line.
+func (m Model) highlightLine(line string) string {
+	if len(m.highlight) == 0 {
+		return line
+	}
+	ranges := search.Ranges(line, m.highlight)
+	if len(ranges) == 0 {
+		return line
+	}
+	var b strings.Builder
+	last := 0
+	for _, r := range ranges {
+		b.WriteString(line[last:r[0]])
+		b.WriteString(m.styles.Match.Render(line[r[0]:r[1]]))
+		last = r[1]
+	}
+	b.WriteString(line[last:])
+	return b.String()
+}
+
+// Clear resets the thread, dropping everything that belonged to the
+// conversation shown: messages, unsent entries, typing, search highlights,
+// the jump target and the selected message.
 func (m *Model) Clear() {
-	m.conversationID = ""
-	m.subject = ""
-	m.messages = nil
-	m.viewport.SetContent("")
-	m.viewport.GotoTop()
+	m.SetConversation("", "")
 }
 
 // HasConversation returns whether a conversation is loaded.
@@ -323,6 +782,12 @@ func (m Model) MessageCount() int {
 	return len(m.messages)
 }
 
+// ViewportTop is the row within the panel that messages start on, below
+// the top border and the title.
+func (m Model) ViewportTop() int {
+	return 2
+}
+
 // VisibleHeight returns the viewport height.
 func (m Model) VisibleHeight() int {
 	if m.viewport.Height < 1 {
@@ -5,9 +5,11 @@ import (
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ggfevans/endorse/internal/config"
 	"github.com/ggfevans/endorse/internal/ui/styles"
+	"github.com/ggfevans/endorse/internal/util"
 )
 
 var ansiRe = regexp.MustCompile(`\x1b\[[0-9;]*[a-zA-Z]`)
@@ -20,6 +22,9 @@ func countRenderedLines(s string) int {
 	return len(strings.Split(s, "\n"))
 }
 
+// testTime is a fixed timestamp for messages whose time doesn't matter.
+var testTime = time.Date(2025, time.March, 4, 10, 30, 0, 0, time.Local)
+
 func newTestThread() Model {
 	theme := config.ThemeByName("")
 	s := styles.New(theme)
@@ -28,9 +33,9 @@ func newTestThread() Model {
 
 func sampleMessages() []Message {
 	return []Message{
-		{ID: "m1", Sender: "Alice Johnson", Body: "Hello there!", Timestamp: "10:30 AM", IsOwn: false},
-		{ID: "m2", Sender: "Me", Body: "Hi Alice, how are you?", Timestamp: "10:31 AM", IsOwn: true},
-		{ID: "m3", Sender: "Alice Johnson", Body: "Doing great, thanks!", Timestamp: "10:32 AM", IsOwn: false},
+		{ID: "m1", Sender: "Alice Johnson", Body: "Hello there!", Timestamp: testTime, IsOwn: false},
+		{ID: "m2", Sender: "Me", Body: "Hi Alice, how are you?", Timestamp: testTime, IsOwn: true},
+		{ID: "m3", Sender: "Alice Johnson", Body: "Doing great, thanks!", Timestamp: testTime, IsOwn: false},
 	}
 }
 
@@ -92,7 +97,7 @@ func TestScrolling(t *testing.T) {
 			ID:        fmt.Sprintf("m%d", i),
 			Sender:    fmt.Sprintf("User %d", i),
 			Body:      fmt.Sprintf("Message number %d with some content", i),
-			Timestamp: "12:00",
+			Timestamp: testTime,
 			IsOwn:     i%2 == 0,
 		})
 	}
@@ -127,7 +132,7 @@ func TestScrollPercent(t *testing.T) {
 			ID:        fmt.Sprintf("m%d", i),
 			Sender:    fmt.Sprintf("User %d", i),
 			Body:      fmt.Sprintf("Message %d", i),
-			Timestamp: "12:00",
+			Timestamp: testTime,
 			IsOwn:     false,
 		})
 	}
@@ -238,8 +243,18 @@ func TestClear(t *testing.T) {
 		t.Errorf("expected MessageCount()=3, got %d", m.MessageCount())
 	}
 
+	m.SetUnsent([]Message{{ID: "pending", Body: "Unsent", Timestamp: testTime}})
+	m.SetTyping("Alice")
+	m.SetHighlight([]string{"hello"})
+	m.JumpTo("missing")
+	m.HoverAt(0)
+
 	m.Clear()
 
+	if m.UnsentCount() != 0 || m.IsTyping() || m.JumpPending() || m.SelectedMessage() != "" || len(m.highlight) != 0 {
+		t.Errorf("expected Clear() to drop unsent, typing, jump, selection and highlights, got %d, %v, %v, %q, %q",
+			m.UnsentCount(), m.IsTyping(), m.JumpPending(), m.SelectedMessage(), m.highlight)
+	}
 	if m.HasConversation() {
 		t.Error("expected HasConversation()=false after Clear()")
 	}
@@ -250,3 +265,376 @@ func TestClear(t *testing.T) {
 		t.Errorf("expected MessageCount()=0 after Clear(), got %d", m.MessageCount())
 	}
 }
+
+func TestPrependMessagesPreservesScroll(t *testing.T) {
+	m := newTestThread()
+	m.SetSize(60, 20)
+	m.SetConversation("conv-1", "Chat")
+
+	var newer []Message
+	for i := 20; i < 40; i++ {
+		newer = append(newer, Message{
+			ID:        fmt.Sprintf("m%d", i),
+			Sender:    fmt.Sprintf("User %d", i),
+			Body:      fmt.Sprintf("Message %d", i),
+			Timestamp: testTime,
+		})
+	}
+	m.SetMessages(newer)
+	m.ScrollUp(10000)
+	m.SetLoadingOlder(true)
+
+	output := stripAnsi(m.View())
+	if !strings.Contains(output, "Loading older") {
+		t.Errorf("expected loading row while older page is in flight, got:\n%s", output)
+	}
+
+	var older []Message
+	for i := 0; i < 20; i++ {
+		older = append(older, Message{
+			ID:        fmt.Sprintf("m%d", i),
+			Sender:    fmt.Sprintf("User %d", i),
+			Body:      fmt.Sprintf("Message %d", i),
+			Timestamp: testTime,
+		})
+	}
+	// Overlap with an already-loaded message should be skipped
+	older = append(older, newer[0])
+	m.PrependMessages(older)
+
+	if m.MessageCount() != 40 {
+		t.Errorf("expected MessageCount()=40 after prepend, got %d", m.MessageCount())
+	}
+	if m.LoadingOlder() {
+		t.Error("expected LoadingOlder()=false after PrependMessages")
+	}
+	if m.AtTop() {
+		t.Error("expected scroll position to stay on the previously visible message, not jump to top")
+	}
+	output = stripAnsi(m.View())
+	if !strings.Contains(output, "Message 20") {
+		t.Errorf("expected previously visible 'Message 20' to remain in view, got:\n%s", output)
+	}
+}
+
+func TestReachedStartMarker(t *testing.T) {
+	m := newTestThread()
+	m.SetSize(60, 20)
+	m.SetConversation("conv-1", "Chat")
+	m.SetMessages(sampleMessages())
+	m.SetReachedStart(true)
+
+	output := stripAnsi(m.View())
+	if !strings.Contains(output, "Beginning of conversation") {
+		t.Errorf("expected start marker in view, got:\n%s", output)
+	}
+
+	m.SetConversation("conv-2", "Other Chat")
+	if m.ReachedStart() {
+		t.Error("expected ReachedStart()=false after SetConversation")
+	}
+}
+
+func TestJumpToPendingMessage(t *testing.T) {
+	m := newTestThread()
+	m.SetSize(60, 20)
+	m.SetConversation("conv-1", "Chat")
+	m.SetHighlight([]string{"needle"})
+	m.JumpTo("m5")
+
+	var msgs []Message
+	for i := 0; i < 40; i++ {
+		body := fmt.Sprintf("Message %d", i)
+		if i == 5 {
+			body = "Message with the needle in it"
+		}
+		msgs = append(msgs, Message{
+			ID:        fmt.Sprintf("m%d", i),
+			Sender:    fmt.Sprintf("User %d", i),
+			Body:      body,
+			Timestamp: testTime,
+		})
+	}
+	m.SetMessages(msgs)
+
+	output := m.View()
+	if !strings.Contains(stripAnsi(output), "needle") {
+		t.Errorf("expected view to be scrolled to the jump target, got:\n%s", stripAnsi(output))
+	}
+	if m.ScrollPercent() > 0.5 {
+		t.Errorf("expected jump target near the top of the thread, got scroll %f", m.ScrollPercent())
+	}
+}
+
+func TestJumpTargetFromOlderPage(t *testing.T) {
+	m := newTestThread()
+	m.SetSize(60, 20)
+	m.SetConversation("conv-1", "Chat")
+	m.JumpTo("m5")
+
+	var msgs []Message
+	for i := 0; i < 40; i++ {
+		msgs = append(msgs, Message{
+			ID:        fmt.Sprintf("m%d", i),
+			Sender:    fmt.Sprintf("User %d", i),
+			Body:      fmt.Sprintf("Message %d", i),
+			Timestamp: testTime.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	m.SetMessages(msgs[20:])
+	if !m.JumpPending() {
+		t.Fatal("expected JumpPending()=true while the target isn't loaded")
+	}
+
+	// A page filling the gap below the target lands in time order
+	m.PrependMessages(msgs[:20])
+	if m.JumpPending() {
+		t.Error("expected JumpPending()=false once the target is loaded")
+	}
+	output := stripAnsi(m.View())
+	if !strings.Contains(output, "Message 5") || strings.Contains(output, "Message 39") {
+		t.Errorf("expected view to be scrolled to the jump target, got:\n%s", output)
+	}
+
+	// Scrolling away drops the target, so later merges don't pull the view back
+	m.ScrollDown(10000)
+	m.MergeMessages(msgs)
+	if m.JumpPending() || !m.viewport.AtBottom() {
+		t.Error("expected the view to stay at the bottom after the user scrolled")
+	}
+}
+
+func TestUnsentMessages(t *testing.T) {
+	m := newTestThread()
+	m.SetSize(80, 20)
+	m.SetConversation("conv-1", "Chat")
+	m.SetMessages(sampleMessages())
+
+	m.SetUnsent([]Message{
+		{ID: "local-1", Sender: "Me", Body: "Are you there?", IsOwn: true, Delivery: Failed},
+	})
+	if m.UnsentCount() != 1 {
+		t.Errorf("expected UnsentCount()=1, got %d", m.UnsentCount())
+	}
+
+	output := stripAnsi(m.View())
+	if !strings.Contains(output, "Are you there?") {
+		t.Errorf("expected unsent body in view, got:\n%s", output)
+	}
+	if !strings.Contains(output, "Not sent") {
+		t.Errorf("expected failed delivery status in view, got:\n%s", output)
+	}
+
+	m.SetConversation("conv-2", "Other")
+	if m.UnsentCount() != 0 {
+		t.Error("expected unsent entries cleared on SetConversation")
+	}
+}
+
+func TestConfirmSentReplacesPlaceholder(t *testing.T) {
+	m := newTestThread()
+	m.SetSize(80, 20)
+	m.SetConversation("conv-1", "Chat")
+	m.SetMessages(sampleMessages())
+	m.SetUnsent([]Message{
+		{ID: "local-1", Sender: "Me", Body: "On my way", IsOwn: true, Delivery: Sending},
+	})
+
+	output := stripAnsi(m.View())
+	if !strings.Contains(output, "Sending") {
+		t.Errorf("expected sending state for placeholder, got:\n%s", output)
+	}
+
+	m.ConfirmSent("local-1", Message{ID: "urn:li:msg:1", Sender: "Me", Body: "On my way", Timestamp: testTime, IsOwn: true})
+	if m.UnsentCount() != 0 {
+		t.Errorf("expected placeholder removed, got %d unsent", m.UnsentCount())
+	}
+	if m.MessageCount() != 4 {
+		t.Errorf("expected confirmed message appended, got %d messages", m.MessageCount())
+	}
+	output = stripAnsi(m.View())
+	if !strings.Contains(output, "✓ Sent") {
+		t.Errorf("expected sent marker after confirmation, got:\n%s", output)
+	}
+
+	// A realtime echo of the same message must not duplicate it
+	m.AppendMessage(Message{ID: "urn:li:msg:1", Sender: "Me", Body: "On my way", Timestamp: testTime, IsOwn: true})
+	if m.MessageCount() != 4 {
+		t.Errorf("expected duplicate ID to update in place, got %d messages", m.MessageCount())
+	}
+	if !strings.Contains(stripAnsi(m.View()), "✓ Sent") {
+		t.Error("expected sent marker to survive a realtime echo")
+	}
+}
+
+func TestMergeMessagesKeepsSentMarker(t *testing.T) {
+	m := newTestThread()
+	m.SetSize(80, 20)
+	m.SetConversation("conv-1", "Chat")
+	m.SetMessages(sampleMessages())
+	m.ConfirmSent("local-1", Message{ID: "m4", Sender: "Me", Body: "See you soon", Timestamp: testTime, IsOwn: true})
+
+	refreshed := append(sampleMessages(),
+		Message{ID: "m4", Sender: "Me", Body: "See you soon", Timestamp: testTime, IsOwn: true},
+		Message{ID: "m5", Sender: "Alice Johnson", Body: "Missed this one", Timestamp: testTime},
+	)
+	m.MergeMessages(refreshed)
+
+	if m.MessageCount() != 5 {
+		t.Errorf("expected 5 messages after merge, got %d", m.MessageCount())
+	}
+	output := stripAnsi(m.View())
+	if !strings.Contains(output, "✓ Sent") || !strings.Contains(output, "Missed this one") {
+		t.Errorf("expected sent marker kept and missed message shown, got:\n%s", output)
+	}
+}
+
+func TestSeenMarkers(t *testing.T) {
+	m := newTestThread()
+	m.SetSize(80, 30)
+	m.SetConversation("conv-1", "Team")
+	m.SetMessages([]Message{
+		{ID: "m1", Sender: "Me", Body: "First", Timestamp: testTime, IsOwn: true},
+		{ID: "m2", Sender: "Me", Body: "Second", Timestamp: testTime, IsOwn: true},
+		{ID: "m3", Sender: "Alice Johnson", Body: "Reply", Timestamp: testTime},
+	})
+
+	now := time.Now()
+	m.SetReceipts([]Receipt{
+		{Reader: "Alice", MessageID: "m3", SeenAt: now.Add(-2 * time.Minute)},
+		{Reader: "Bob", MessageID: "m1", SeenAt: now.Add(-5 * time.Minute)},
+	})
+
+	output := stripAnsi(m.View())
+	if !strings.Contains(output, "Seen by Alice · 2m ago") {
+		t.Errorf("expected Alice's marker under the last own message she read, got:\n%s", output)
+	}
+	if !strings.Contains(output, "Seen by Bob · 5m ago") {
+		t.Errorf("expected Bob's marker under the first message, got:\n%s", output)
+	}
+	if strings.Index(output, "Seen by Bob") > strings.Index(output, "Second") {
+		t.Errorf("expected Bob's marker before the second message, got:\n%s", output)
+	}
+
+	// Readers of the same message are grouped, with the latest time shown
+	m.SetReceipts([]Receipt{
+		{Reader: "Alice", MessageID: "m2", SeenAt: now.Add(-3 * time.Minute)},
+		{Reader: "Bob", MessageID: "m2", SeenAt: now.Add(-time.Minute)},
+	})
+	output = stripAnsi(m.View())
+	if !strings.Contains(output, "Seen by Alice, Bob · 1m ago") {
+		t.Errorf("expected grouped readers, got:\n%s", output)
+	}
+
+	// A receipt for a message that isn't loaded only marks own messages
+	// sent before it was seen
+	m.SetMessages([]Message{
+		{ID: "m1", Sender: "Me", Body: "First", Timestamp: now.Add(-10 * time.Minute), IsOwn: true},
+		{ID: "m2", Sender: "Me", Body: "Second", Timestamp: now.Add(-time.Minute), IsOwn: true},
+	})
+	m.SetReceipts([]Receipt{{Reader: "Alice", MessageID: "unloaded", SeenAt: now.Add(-5 * time.Minute)}})
+	output = stripAnsi(m.View())
+	if i := strings.Index(output, "Seen by Alice"); i < 0 || i > strings.Index(output, "Second") {
+		t.Errorf("expected Alice's marker under the message sent before she read, got:\n%s", output)
+	}
+	m.SetReceipts([]Receipt{{Reader: "Alice", MessageID: "unloaded", SeenAt: now.Add(-time.Hour)}})
+	if output = stripAnsi(m.View()); strings.Contains(output, "Seen by") {
+		t.Errorf("expected no marker for a receipt older than every own message, got:\n%s", output)
+	}
+}
+
+func TestTimestampsLiveAndToggle(t *testing.T) {
+	m := newTestThread()
+	m.SetSize(80, 30)
+	m.SetConversation("conv-1", "Alice Johnson")
+	sent := time.Now().Add(-30 * time.Second)
+	m.SetMessages([]Message{{ID: "m1", Sender: "Alice Johnson", Body: "Hello", Timestamp: sent}})
+
+	if out := stripAnsi(m.View()); !strings.Contains(out, "just now") {
+		t.Fatalf("expected a relative timestamp, got:\n%s", out)
+	}
+
+	// Time passes; a refresh re-renders without the message changing
+	m.messages[0].Timestamp = sent.Add(-5 * time.Minute)
+	m.Refresh()
+	if out := stripAnsi(m.View()); !strings.Contains(out, "5m ago") {
+		t.Errorf("expected the refresh to update the relative time, got:\n%s", out)
+	}
+
+	m.SetAbsoluteTimes(true)
+	if out := stripAnsi(m.View()); !strings.Contains(out, util.AbsoluteTime(m.messages[0].Timestamp)) {
+		t.Errorf("expected an absolute timestamp, got:\n%s", out)
+	}
+
+	m.Focus()
+	if m.SelectedMessage() != "m1" {
+		t.Fatalf("expected focus to select the visible message, got %q", m.SelectedMessage())
+	}
+	if out := stripAnsi(m.View()); !strings.Contains(out, util.ExactTime(m.messages[0].Timestamp)) {
+		t.Errorf("expected the selected message to show its exact time, got:\n%s", out)
+	}
+
+	m.Blur()
+	m.HoverAt(1)
+	if m.SelectedMessage() != "m1" {
+		t.Errorf("expected hovering a message row to select it, got %q", m.SelectedMessage())
+	}
+	m.HoverAt(-1)
+	if m.SelectedMessage() != "m1" {
+		t.Error("expected rows outside the viewport to be ignored")
+	}
+}
+
+func TestDaySeparatorsAndGapSplits(t *testing.T) {
+	m := newTestThread()
+	m.SetSize(80, 40)
+	m.SetConversation("conv-1", "Alice Johnson")
+	m.SetGroupGap(30 * time.Minute)
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	old := time.Date(2025, time.March, 3, 9, 0, 0, 0, now.Location())
+	m.SetMessages([]Message{
+		{ID: "m1", Sender: "Alice Johnson", Body: "Long ago", Timestamp: old},
+		{ID: "m2", Sender: "Alice Johnson", Body: "Same morning", Timestamp: old.Add(10 * time.Minute)},
+		{ID: "m3", Sender: "Alice Johnson", Body: "After lunch", Timestamp: old.Add(4 * time.Hour)},
+		{ID: "m4", Sender: "Alice Johnson", Body: "Yesterday's note", Timestamp: today.Add(-12 * time.Hour)},
+		{ID: "m5", Sender: "Me", Body: "Morning!", Timestamp: today.Add(time.Minute), IsOwn: true},
+	})
+
+	out := stripAnsi(m.View())
+	for _, want := range []string{"Mon 3 Mar 2025", "Yesterday", "Today"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected a %q separator, got:\n%s", want, out)
+		}
+	}
+	if strings.Count(out, "Alice Johnson") != 1 {
+		t.Errorf("expected the 1:1 sender header to stay skipped, got:\n%s", out)
+	}
+
+	lines := strings.Split(out, "\n")
+	between := func(a, b string) string {
+		var ia, ib int
+		for i, l := range lines {
+			if strings.Contains(l, a) {
+				ia = i
+			}
+			if strings.Contains(l, b) {
+				ib = i
+			}
+		}
+		return strings.Join(lines[ia+1:ib], "\n")
+	}
+	if strings.Contains(between("Long ago", "Same morning"), "───") {
+		t.Error("expected messages minutes apart to stay in one group")
+	}
+	if !strings.Contains(between("Same morning", "After lunch"), "───") {
+		t.Error("expected a gap longer than the threshold to split the group")
+	}
+
+	m.SetGroupGap(0)
+	lines = strings.Split(stripAnsi(m.View()), "\n")
+	if strings.Contains(between("Same morning", "After lunch"), "───") {
+		t.Error("expected gap splitting to be disabled at zero")
+	}
+}
@@ -32,10 +32,61 @@ func RelativeTime(t time.Time) string {
 	}
 }
 
+// AbsoluteTime formats a timestamp as a clock time, adding the date when it
+// isn't today.
+func AbsoluteTime(t time.Time) string {
+	now := time.Now()
+	switch {
+	case isToday(t, now):
+		return t.Format("3:04 PM")
+	case t.Year() == now.Year():
+		return t.Format("Jan 2 3:04 PM")
+	default:
+		return t.Format("Jan 2, 2006 3:04 PM")
+	}
+}
+
+// ExactTime formats a timestamp with its full date and time to the second.
+func ExactTime(t time.Time) string {
+	return t.Format("Mon Jan 2, 2006 3:04:05 PM")
+}
+
+// FormatTime formats a timestamp relatively or absolutely. The zero time
+// formats as an empty string.
+func FormatTime(t time.Time, absolute bool) string {
+	switch {
+	case t.IsZero():
+		return ""
+	case absolute:
+		return AbsoluteTime(t)
+	default:
+		return RelativeTime(t)
+	}
+}
+
+// DayLabel names a timestamp's calendar day: "Today", "Yesterday", or a
+// full date like "Mon 3 Mar 2025".
+func DayLabel(t time.Time) string {
+	now := time.Now()
+	switch {
+	case isToday(t, now):
+		return "Today"
+	case isYesterday(t, now):
+		return "Yesterday"
+	default:
+		return t.Format("Mon 2 Jan 2006")
+	}
+}
+
+// SameDay reports whether two timestamps fall on the same calendar day.
+func SameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
 func isToday(t, now time.Time) bool {
-	ty, tm, td := t.Date()
-	ny, nm, nd := now.Date()
-	return ty == ny && tm == nm && td == nd
+	return SameDay(t, now)
 }
 
 func isYesterday(t, now time.Time) bool {
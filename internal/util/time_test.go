@@ -138,3 +138,58 @@ func TestRelativeTimeNotEmpty(t *testing.T) {
 		}
 	}
 }
+
+func TestAbsoluteTime(t *testing.T) {
+	now := time.Now()
+	if got, want := AbsoluteTime(now), now.Format("3:04 PM"); got != want {
+		t.Errorf("AbsoluteTime(now) = %q, want %q", got, want)
+	}
+	old := time.Date(2020, time.March, 15, 10, 4, 0, 0, now.Location())
+	if got, want := AbsoluteTime(old), "Mar 15, 2020 10:04 AM"; got != want {
+		t.Errorf("AbsoluteTime(old) = %q, want %q", got, want)
+	}
+	if got, want := ExactTime(old), "Sun Mar 15, 2020 10:04:00 AM"; got != want {
+		t.Errorf("ExactTime() = %q, want %q", got, want)
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	if got := FormatTime(time.Time{}, false); got != "" {
+		t.Errorf("FormatTime(zero) = %q, want empty", got)
+	}
+	recent := time.Now().Add(-5 * time.Minute)
+	if got := FormatTime(recent, false); got != "5m ago" {
+		t.Errorf("FormatTime(relative) = %q, want %q", got, "5m ago")
+	}
+	if got := FormatTime(recent, true); got != AbsoluteTime(recent) {
+		t.Errorf("FormatTime(absolute) = %q, want %q", got, AbsoluteTime(recent))
+	}
+}
+
+func TestDayLabel(t *testing.T) {
+	now := time.Now()
+	noon := time.Date(now.Year(), now.Month(), now.Day(), 12, 0, 0, 0, now.Location())
+	tests := []struct {
+		input time.Time
+		want  string
+	}{
+		{noon, "Today"},
+		{noon.AddDate(0, 0, -1), "Yesterday"},
+		{time.Date(2025, time.March, 3, 9, 0, 0, 0, now.Location()), "Mon 3 Mar 2025"},
+	}
+	for _, tt := range tests {
+		if got := DayLabel(tt.input); got != tt.want {
+			t.Errorf("DayLabel(%v) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestSameDay(t *testing.T) {
+	late := time.Date(2025, time.March, 3, 23, 59, 0, 0, time.UTC)
+	if !SameDay(late, late.Add(-23*time.Hour)) {
+		t.Error("expected times on the same date to be the same day")
+	}
+	if SameDay(late, late.Add(2*time.Minute)) {
+		t.Error("expected times either side of midnight to be different days")
+	}
+}